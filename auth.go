@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// accessPolicy is the authentication requirement of an rpc.
+type accessPolicy int
+
+const (
+	policyPublic accessPolicy = iota
+	// policyAuthenticated accepts any logged-in user, whatever the role
+	policyAuthenticated
+	policyCustomer
+	policySupplier
+	policyAdmin
+	// policyOptional authenticates callers who send a token, for public rpcs
+	// that show more to some roles; anonymous callers get no principal
+	policyOptional
+)
+
+// methodPolicies lists the policy of every rpc served. Methods missing from
+// the list are rejected.
+var methodPolicies = map[string]accessPolicy{
+	"/ecommerce.ReviewService/Ping":                     policyPublic,
+	"/ecommerce.ReviewService/GetAllReviewByProductID":  policyPublic,
+	"/ecommerce.ReviewService/CreateReview":             policyCustomer,
+	"/ecommerce.ReviewService/UpdateReview":             policyCustomer,
+	"/ecommerce.ReviewService/DeleteReview":             policyAuthenticated,
+	"/ecommerce.ReviewService/ListPendingReviews":       policyAdmin,
+	"/ecommerce.ReviewService/ModerateReviews":          policyAdmin,
+	"/ecommerce.ReviewService/GetModerationHistory":     policyAdmin,
+	"/ecommerce.ReviewService/ReportReview":             policyAuthenticated,
+	"/ecommerce.ReviewService/ListReports":              policyAdmin,
+	"/ecommerce.ReviewService/VoteReview":               policyAuthenticated,
+	"/ecommerce.ReviewService/RetractVote":              policyAuthenticated,
+	"/ecommerce.ReviewService/ReplyToReview":            policySupplier,
+	"/ecommerce.ReviewService/CreateComment":            policyAuthenticated,
+	"/ecommerce.ReviewService/UpdateComment":            policyAuthenticated,
+	"/ecommerce.ReviewService/DeleteComment":            policyAuthenticated,
+	"/ecommerce.ReviewService/ListComments":             policyPublic,
+	"/ecommerce.ReviewService/ListPendingComments":      policyAdmin,
+	"/ecommerce.ReviewService/ModerateComments":         policyAdmin,
+	"/ecommerce.ReviewService/AskQuestion":              policyAuthenticated,
+	"/ecommerce.ReviewService/AnswerQuestion":           policyAuthenticated,
+	"/ecommerce.ReviewService/ListQuestions":            policyPublic,
+	"/ecommerce.ReviewService/UpvoteAnswer":             policyAuthenticated,
+	"/ecommerce.ReviewService/GetProductRatingSummary":  policyPublic,
+	"/ecommerce.ReviewService/CreateTag":                policyAdmin,
+	"/ecommerce.ReviewService/UpdateTag":                policyAdmin,
+	"/ecommerce.ReviewService/ListTags":                 policyOptional,
+	"/ecommerce.ReviewService/GetProductTagFrequencies": policyPublic,
+	"/ecommerce.ReviewService/SearchReviews":            policyPublic,
+	"/ecommerce.ReviewService/GetMyReviews":             policyAuthenticated,
+	"/ecommerce.ReviewService/GetReviewsByUser":         policyAdmin,
+	"/ecommerce.ReviewService/GetReview":                policyOptional,
+	"/ecommerce.ReviewService/GetReviews":               policyPublic,
+	"/ecommerce.ReviewService/ListReviewableOrders":     policyCustomer,
+	"/ecommerce.ReviewService/WatchProductReviews":      policyPublic,
+	"/grpc.health.v1.Health/Check":                      policyPublic,
+	"/grpc.health.v1.Health/Watch":                      policyPublic,
+}
+
+// principal is the authenticated caller of an rpc.
+type principal struct {
+	userID int64
+	role   pb.UserRole
+}
+
+func (p principal) isAdmin() bool {
+	return p.role == pb.UserRole_admin
+}
+
+type principalKey struct{}
+
+func withPrincipal(ctx context.Context, p principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// principalFromContext returns the caller resolved by the auth interceptor.
+// It is only present for non-public rpcs.
+func principalFromContext(ctx context.Context) (principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(principal)
+	return p, ok
+}
+
+// mustPrincipal returns the caller of an rpc whose policy is not public.
+func mustPrincipal(ctx context.Context) (principal, error) {
+	p, ok := principalFromContext(ctx)
+	if !ok {
+		return principal{}, errUnauthenticated("MISSING_CREDENTIALS", nil)
+	}
+	return p, nil
+}
+
+// forwardMetadata copies the incoming metadata, which carries the caller's
+// token, to the outgoing context for calls to other services.
+func forwardMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// authenticator resolves the caller of an rpc according to its policy.
+type authenticator interface {
+	authenticate(ctx context.Context, policy accessPolicy) (principal, error)
+}
+
+// remoteAuthenticator asks auth-service to authorize the caller's token.
+type remoteAuthenticator struct {
+	authClient pb.AuthServiceClient
+}
+
+func (a remoteAuthenticator) authenticate(ctx context.Context, policy accessPolicy) (principal, error) {
+	if _, ok := metadata.FromIncomingContext(ctx); !ok {
+		return principal{}, errUnauthenticated("MISSING_CREDENTIALS", nil)
+	}
+	ctx = forwardMetadata(ctx)
+
+	var (
+		claims *pb.UserClaimsResponse
+		err    error
+	)
+	switch policy {
+	case policyCustomer:
+		claims, err = a.authClient.CustomerAuthorization(ctx, _empty)
+	case policySupplier:
+		claims, err = a.authClient.SupplierAuthorization(ctx, _empty)
+	case policyAdmin:
+		claims, err = a.authClient.AdminAuthorization(ctx, _empty)
+	default:
+		claims, err = a.authClient.GetUserClaims(ctx, _empty)
+	}
+	if err != nil {
+		// auth-service rejecting the caller is not a failure of its own
+		switch status.Code(err) {
+		case codes.Unauthenticated:
+			return principal{}, errUnauthenticated("INVALID_TOKEN", err)
+		case codes.PermissionDenied:
+			return principal{}, errForbidden("ROLE_NOT_ALLOWED")
+		}
+		return principal{}, fromDependency("auth-service", err)
+	}
+	return principalFromClaims(claims.GetId(), claims.GetUserRole(), policy)
+}
+
+// principalFromClaims validates the identity returned by the token issuer.
+func principalFromClaims(id string, role pb.UserRole, policy accessPolicy) (principal, error) {
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || userID <= 0 {
+		return principal{}, errUnauthenticated("INVALID_CLAIMS", fmt.Errorf("malformed user id %q", id))
+	}
+	if _, ok := pb.UserRole_name[int32(role)]; !ok {
+		return principal{}, errUnauthenticated("INVALID_CLAIMS", fmt.Errorf("unknown user role %d", role))
+	}
+	p := principal{userID: userID, role: role}
+	if !permits(policy, p) {
+		return principal{}, errForbidden("ROLE_NOT_ALLOWED")
+	}
+	return p, nil
+}
+
+// permits reports whether the caller satisfies the policy.
+func permits(policy accessPolicy, p principal) bool {
+	switch policy {
+	case policyPublic, policyAuthenticated, policyOptional:
+		return true
+	case policyCustomer:
+		return p.role == pb.UserRole_customer
+	case policySupplier:
+		return p.role == pb.UserRole_supplier
+	case policyAdmin:
+		return p.role == pb.UserRole_admin
+	default:
+		return false
+	}
+}
+
+func authorize(ctx context.Context, auth authenticator, method string) (context.Context, error) {
+	policy, ok := methodPolicies[method]
+	if !ok {
+		log.Printf("no access policy for %s", method)
+		return nil, errForbidden("NO_ACCESS_POLICY")
+	}
+	if policy == policyPublic {
+		return ctx, nil
+	}
+	if policy == policyOptional {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("authorization")) == 0 {
+			return ctx, nil
+		}
+	}
+	p, err := auth.authenticate(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+	return withPrincipal(ctx, p), nil
+}
+
+// authUnaryInterceptor authenticates the caller once per rpc and stores the
+// principal in the context.
+func authUnaryInterceptor(auth authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, auth, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor is the streaming counterpart of authUnaryInterceptor.
+func authStreamInterceptor(auth authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), auth, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeOptionalPolicy(t *testing.T) {
+	const method = "/ecommerce.ReviewService/ListTags"
+	tests := []struct {
+		name          string
+		md            metadata.MD
+		wantCalls     int
+		wantPrincipal bool
+	}{
+		{"anonymous", nil, 0, false},
+		{"other metadata", metadata.Pairs("x-request-id", "1"), 0, false},
+		{"with token", metadata.Pairs("authorization", "Bearer token"), 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			auth := &countingAuthenticator{user: 1}
+			ctx, err := authorize(ctx, auth, method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if auth.calls != tt.wantCalls {
+				t.Errorf("authenticator called %d times, want %d", auth.calls, tt.wantCalls)
+			}
+			if _, ok := principalFromContext(ctx); ok != tt.wantPrincipal {
+				t.Errorf("principal present = %v, want %v", ok, tt.wantPrincipal)
+			}
+		})
+	}
+}
+
+func TestAuthErrorsAreLocalized(t *testing.T) {
+	const method = "/ecommerce.ReviewService/CreateReview"
+	auth := localAuthenticator{}
+	tests := []struct {
+		name       string
+		method     string
+		md         metadata.MD
+		wantCode   codes.Code
+		wantReason string
+		wantMsg    string
+	}{
+		{"missing token", method, metadata.Pairs("accept-language", "en"), codes.Unauthenticated, "MISSING_CREDENTIALS", "Please sign in to continue"},
+		{"empty token", method, metadata.Pairs("authorization", "  ", "accept-language", "vi"), codes.Unauthenticated, "MISSING_CREDENTIALS", "Vui lòng đăng nhập để tiếp tục"},
+		{"malformed token", method, metadata.Pairs("authorization", "Bearer abc", "accept-language", "en"), codes.Unauthenticated, "INVALID_TOKEN", "Please sign in to continue"},
+		{"unknown method", "/ecommerce.ReviewService/Unknown", metadata.Pairs("accept-language", "en"), codes.PermissionDenied, "NO_ACCESS_POLICY", "You are not allowed to perform this action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := authorize(ctx, auth, tt.method)
+			st := status.Convert(toStatusError(ctx, tt.method, err))
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg {
+				t.Errorf("got %v %q, want %v %q", st.Code(), st.Message(), tt.wantCode, tt.wantMsg)
+			}
+			var reason string
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.ErrorInfo); ok {
+					reason = info.GetReason()
+				}
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestPrincipalFromClaims(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		role       pb.UserRole
+		policy     accessPolicy
+		wantReason string
+	}{
+		{"valid", "7", pb.UserRole_customer, policyCustomer, ""},
+		{"malformed id", "seven", pb.UserRole_customer, policyCustomer, "INVALID_CLAIMS"},
+		{"unknown role", "7", pb.UserRole(42), policyAuthenticated, "INVALID_CLAIMS"},
+		{"wrong role", "7", pb.UserRole_customer, policyAdmin, "ROLE_NOT_ALLOWED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := principalFromClaims(tt.id, tt.role, tt.policy)
+			var reason string
+			if re, ok := err.(*reviewError); ok {
+				reason = re.reason
+			} else if err != nil {
+				t.Fatalf("got %T %v, want a reviewError", err, err)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
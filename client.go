@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// idempotentMethods are safe to retry because they only read data.
+var idempotentMethods = map[string]bool{
+	"/ecommerce.AuthService/GetUserClaims":              true,
+	"/ecommerce.AuthService/CustomerAuthorization":      true,
+	"/ecommerce.AuthService/SupplierAuthorization":      true,
+	"/ecommerce.AuthService/AdminAuthorization":         true,
+	"/ecommerce.OrderService/CheckOrderIsHandled":       true,
+	"/ecommerce.OrderService/GetHandledOrderByCustomer": true,
+	"/ecommerce.OrderService/GetHandledOrderBySupllier": true,
+}
+
+// clientPolicy configures how calls to a dependency are made.
+type clientPolicy struct {
+	// timeout bounds every attempt, on top of the caller's own deadline
+	timeout time.Duration
+	// maxAttempts is the number of tries for idempotent unary calls
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// dialDependency dials a dependency with per-call timeouts, retries of
+// idempotent calls and a circuit breaker in front of every call.
+func dialDependency(addr string, creds credentials.TransportCredentials, policy clientPolicy, breaker *circuitBreaker) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(policy.unaryInterceptor(breaker)),
+		grpc.WithChainStreamInterceptor(policy.streamInterceptor(breaker)),
+	)
+}
+
+func (p clientPolicy) unaryInterceptor(breaker *circuitBreaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		attempts := 1
+		if idempotentMethods[method] && p.maxAttempts > 1 {
+			attempts = p.maxAttempts
+		}
+
+		var err error
+		for attempt := 0; attempt < attempts; attempt++ {
+			if attempt > 0 {
+				if waitErr := sleepCtx(ctx, backoffDelay(p.backoff, attempt)); waitErr != nil {
+					return err
+				}
+			}
+			if err = breaker.allow(); err != nil {
+				return err
+			}
+
+			callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+			err = invoker(callCtx, method, req, reply, cc, opts...)
+			cancel()
+			breaker.done(ctx, err)
+
+			// stop when the call succeeded, failed because of the request
+			// itself, or the caller gave up
+			if err == nil || !isRetryable(err) || ctx.Err() != nil {
+				return err
+			}
+			log.Printf("%s attempt %d/%d failed: %v", method, attempt+1, attempts, err)
+		}
+		return err
+	}
+}
+
+func (p clientPolicy) streamInterceptor(breaker *circuitBreaker) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := breaker.allow(); err != nil {
+			return nil, err
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		stream, err := streamer(callCtx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+			breaker.done(ctx, err)
+			return nil, err
+		}
+		return &trackedStream{ClientStream: stream, ctx: ctx, desc: desc, cancel: cancel, breaker: breaker}, nil
+	}
+}
+
+// trackedStream releases the stream timeout and reports the outcome to the
+// circuit breaker once the stream is finished.
+type trackedStream struct {
+	grpc.ClientStream
+	// ctx is the caller's context, without the stream timeout
+	ctx     context.Context
+	desc    *grpc.StreamDesc
+	cancel  context.CancelFunc
+	breaker *circuitBreaker
+	once    sync.Once
+}
+
+func (s *trackedStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	// io.EOF means the stream was aborted, its status is returned by RecvMsg
+	if err != nil && err != io.EOF {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *trackedStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *trackedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	// client streams and unary-response streams end with their single response
+	if err != nil || !s.desc.ServerStreams {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *trackedStream) finish(err error) {
+	s.once.Do(func() {
+		if err == io.EOF {
+			err = nil
+		}
+		s.breaker.done(s.ctx, err)
+		s.cancel()
+	})
+}
+
+// isRetryable reports whether a failed call may succeed when tried again.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoffDelay returns an exponential delay with full jitter.
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	max := base << uint(attempt-1)
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker fails calls fast with Unavailable after failureThreshold
+// consecutive failures of a dependency. After openDuration a single probe
+// call is let through; its outcome closes or reopens the breaker.
+type circuitBreaker struct {
+	name             string
+	failureThreshold int
+	openDuration     time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(name string, failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+	}
+}
+
+// allow returns an Unavailable error when the call must not be attempted.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen && time.Since(b.openedAt) >= b.openDuration {
+		b.setState(breakerHalfOpen)
+	}
+	switch {
+	case b.state == breakerOpen:
+		return status.Errorf(codes.Unavailable, "%s: circuit breaker is open", b.name)
+	case b.state == breakerHalfOpen && b.probing:
+		return status.Errorf(codes.Unavailable, "%s: circuit breaker is half-open", b.name)
+	case b.state == breakerHalfOpen:
+		b.probing = true
+	}
+	return nil
+}
+
+// done reports the outcome of a call that allow let through. Calls ended by
+// the caller's own cancelation or deadline say nothing about the dependency,
+// they only give up the probe slot.
+func (b *circuitBreaker) done(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		b.abandon()
+		return
+	}
+	b.record(err)
+}
+
+// abandon reports that a call allow let through ended without an outcome.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record reports the outcome of a call that allow let through.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !isDependencyFailure(err) {
+		b.failures = 0
+		if b.state != breakerClosed {
+			b.setState(breakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.failureThreshold {
+		b.openedAt = time.Now()
+		b.setState(breakerOpen)
+	}
+}
+
+func (b *circuitBreaker) setState(state breakerState) {
+	if b.state != state {
+		log.Printf("%s circuit breaker: %s -> %s", b.name, b.state, state)
+	}
+	b.state = state
+}
+
+// currentState returns the breaker state for diagnostics.
+func (b *circuitBreaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// check exposes the breaker state as a health dependency.
+func (b *circuitBreaker) check(_ context.Context) error {
+	if state := b.currentState(); state != breakerClosed {
+		return fmt.Errorf("circuit breaker is %s", state)
+	}
+	return nil
+}
+
+// isDependencyFailure reports whether err means the dependency is unhealthy,
+// as opposed to the request being rejected.
+func isDependencyFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClientStream fails SendMsg with sendErr.
+type fakeClientStream struct {
+	grpc.ClientStream
+	sendErr error
+}
+
+func (s *fakeClientStream) SendMsg(interface{}) error {
+	return s.sendErr
+}
+
+// halfOpenBreaker returns a breaker whose next call is the half-open probe.
+func halfOpenBreaker(t *testing.T) *circuitBreaker {
+	t.Helper()
+	breaker := newCircuitBreaker("test", 1, time.Millisecond)
+	if err := breaker.allow(); err != nil {
+		t.Fatal(err)
+	}
+	breaker.record(status.Error(codes.Unavailable, "down"))
+	time.Sleep(2 * time.Millisecond)
+	return breaker
+}
+
+func TestTrackedStreamFinishesOnSendError(t *testing.T) {
+	tests := []struct {
+		name      string
+		sendErr   error
+		wantState breakerState
+		// wantAllow is whether a second probe is let through right away
+		wantAllow bool
+	}{
+		{"failed send reopens", status.Error(codes.Unavailable, "down"), breakerOpen, false},
+		{"aborted stream waits for recv", io.EOF, breakerHalfOpen, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := halfOpenBreaker(t)
+			if err := breaker.allow(); err != nil {
+				t.Fatalf("probe rejected: %v", err)
+			}
+
+			canceled := false
+			stream := &trackedStream{
+				ClientStream: &fakeClientStream{sendErr: tt.sendErr},
+				ctx:          context.Background(),
+				desc:         &grpc.StreamDesc{ClientStreams: true},
+				cancel:       func() { canceled = true },
+				breaker:      breaker,
+			}
+			_ = stream.SendMsg(nil)
+
+			if got := breaker.currentState(); got != tt.wantState {
+				t.Errorf("state = %s, want %s", got, tt.wantState)
+			}
+			if finished := tt.sendErr != io.EOF; canceled != finished {
+				t.Errorf("stream timeout released = %v, want %v", canceled, finished)
+			}
+			if err := breaker.allow(); (err == nil) != tt.wantAllow {
+				t.Errorf("allow() = %v, want allowed %v", err, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestBreakerIgnoresCallerCancelation(t *testing.T) {
+	breaker := halfOpenBreaker(t)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	breaker.done(ctx, status.Error(codes.DeadlineExceeded, "caller deadline"))
+
+	if got := breaker.currentState(); got != breakerHalfOpen {
+		t.Errorf("state = %s, want %s", got, breakerHalfOpen)
+	}
+	// the probe slot is free again
+	if err := breaker.allow(); err != nil {
+		t.Errorf("next probe rejected: %v", err)
+	}
+}
+
+func TestBreakerCountsDependencyDeadline(t *testing.T) {
+	breaker := newCircuitBreaker("test", 2, time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := breaker.allow(); err != nil {
+			t.Fatal(err)
+		}
+		breaker.done(context.Background(), status.Error(codes.DeadlineExceeded, "slow"))
+	}
+	if got := breaker.currentState(); got != breakerOpen {
+		t.Errorf("state = %s, want %s", got, breakerOpen)
+	}
+}
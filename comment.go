@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+const maxCommentLength = 1000
+
+func (srv reviewService) CreateComment(ctx context.Context, req *pb.CreateCommentRequest) (*pb.CreateCommentResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := validateCommentContent(req.GetContent())
+	if req.GetReviewId() <= 0 {
+		violations = append(violations, fieldViolation{field: "review_id", description: msgReviewIDInvalid})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	// comments are screened and moderated like reviews
+	status, reason, err := srv.screenContent("content", req.GetContent(), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment repository.ReviewComment
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		review, err := lockPublishedReview(ctx, q, req.GetReviewId())
+		if err != nil {
+			return err
+		}
+
+		var parentID sql.NullInt64
+		if req.GetParentId() != 0 {
+			// replies only go one level deep, under a published comment
+			parent, err := q.GetComment(ctx, req.GetParentId())
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			if err != nil || parent.ReviewID != review.ID || parent.ParentID.Valid || parent.Status != repository.ReviewStatusApproved {
+				return errInvalidArgument(fieldViolation{field: "parent_id", description: msgCommentParentInvalid})
+			}
+			parentID = sql.NullInt64{Int64: parent.ID, Valid: true}
+		}
+
+		comment, err = q.InsertComment(ctx, repository.InsertCommentParams{
+			ReviewID:         review.ID,
+			ParentID:         parentID,
+			UserID:           caller.userID,
+			Content:          req.GetContent(),
+			Status:           status,
+			ModerationReason: reason.String(),
+		})
+		if err != nil {
+			return err
+		}
+		if status != repository.ReviewStatusApproved {
+			return nil
+		}
+		return q.RefreshCommentCount(ctx, review.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := localize(ctx, msgCommentCreated)
+	if comment.Status == repository.ReviewStatusPending {
+		message = localize(ctx, msgCommentPendingModeration)
+	}
+	return &pb.CreateCommentResponse{
+		Message: message,
+		Comment: toPbComment(comment),
+	}, nil
+}
+
+func (srv reviewService) UpdateComment(ctx context.Context, req *pb.UpdateCommentRequest) (*pb.UpdateCommentResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := validateCommentContent(req.GetContent())
+	if req.GetCommentId() <= 0 {
+		violations = append(violations, fieldViolation{field: "comment_id", description: msgCommentIDInvalid})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	// edited content is screened again like a new comment
+	screened, reason, err := srv.screenContent("content", req.GetContent(), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var status repository.ReviewStatus
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		comment, err := lockOwnComment(ctx, q, req.GetCommentId(), caller)
+		if err != nil {
+			return err
+		}
+		status = editedStatus(comment.Status, screened)
+		_, err = q.UpdateCommentContent(ctx, repository.UpdateCommentContentParams{
+			ID:               comment.ID,
+			Content:          req.GetContent(),
+			Status:           status,
+			ModerationReason: reason.String(),
+		})
+		if err != nil {
+			return err
+		}
+		if comment.Status != repository.ReviewStatusApproved && status != repository.ReviewStatusApproved {
+			return nil
+		}
+		return q.RefreshCommentCount(ctx, comment.ReviewID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := localize(ctx, msgCommentUpdated)
+	if status == repository.ReviewStatusPending {
+		message = localize(ctx, msgCommentUpdatedPendingModeration)
+	}
+	return &pb.UpdateCommentResponse{
+		Message: message,
+	}, nil
+}
+
+func (srv reviewService) DeleteComment(ctx context.Context, req *pb.DeleteCommentRequest) (*pb.DeleteCommentResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		comment, err := lockOwnComment(ctx, q, req.GetCommentId(), caller)
+		if err != nil {
+			return err
+		}
+		// replies are deleted with their parent
+		if err := q.DeleteComment(ctx, comment.ID); err != nil {
+			return err
+		}
+		return q.RefreshCommentCount(ctx, comment.ReviewID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteCommentResponse{
+		Message: localize(ctx, msgCommentDeleted),
+	}, nil
+}
+
+func (srv reviewService) ListComments(ctx context.Context, req *pb.ListCommentsRequest) (*pb.ListCommentsResponse, error) {
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	review, err := srv.queries.GetReviewByID(ctx, req.GetReviewId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound("review", req.GetReviewId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != repository.ReviewStatusApproved {
+		return nil, errNotFound("review", req.GetReviewId())
+	}
+
+	comments, err := srv.queries.ListTopLevelComments(ctx, repository.ListTopLevelCommentsParams{
+		ReviewID: review.ID,
+		AfterID:  afterID,
+		Limit:    pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return &pb.ListCommentsResponse{}, nil
+	}
+
+	result := make([]*pb.Comment, 0, len(comments))
+	byID := make(map[int64]*pb.Comment, len(comments))
+	parentIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		pbComment := toPbComment(comment)
+		result = append(result, pbComment)
+		byID[comment.ID] = pbComment
+		parentIDs = append(parentIDs, comment.ID)
+	}
+
+	replies, err := srv.queries.ListCommentReplies(ctx, parentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		parent := byID[reply.ParentID.Int64]
+		parent.ListReply = append(parent.ListReply, toPbComment(reply))
+	}
+
+	return &pb.ListCommentsResponse{
+		ListComment:   result,
+		NextPageToken: nextPageToken(len(comments), pageSize, comments[len(comments)-1].ID),
+	}, nil
+}
+
+func (srv reviewService) ListPendingComments(ctx context.Context, req *pb.ListPendingCommentsRequest) (*pb.ListPendingCommentsResponse, error) {
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	comments, err := srv.queries.ListCommentsByStatus(ctx, repository.ListCommentsByStatusParams{
+		Status:  repository.ReviewStatusPending,
+		AfterID: afterID,
+		Limit:   pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListPendingCommentsResponse{}
+	for _, comment := range comments {
+		resp.ListComment = append(resp.ListComment, toPbComment(comment))
+	}
+	if len(comments) > 0 {
+		resp.NextPageToken = nextPageToken(len(comments), pageSize, comments[len(comments)-1].ID)
+	}
+	return resp, nil
+}
+
+func (srv reviewService) ModerateComments(ctx context.Context, req *pb.ModerateCommentsRequest) (*pb.ModerateCommentsResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, ok := moderationTransitions[req.GetAction()]
+	var violations []fieldViolation
+	if !ok {
+		violations = append(violations, fieldViolation{field: "action", description: msgModerationActionInvalid})
+	}
+	if n := len(req.GetCommentIds()); n == 0 || n > maxModerationBatch {
+		violations = append(violations, fieldViolation{field: "comment_ids", description: msgModerationBatchSize, args: []interface{}{maxModerationBatch}})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	resp := &pb.ModerateCommentsResponse{}
+	seen := make(map[int64]bool, len(req.GetCommentIds()))
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		if err := lockCommentReviews(ctx, q, req.GetCommentIds()); err != nil {
+			return err
+		}
+
+		var reviewIDs []int64
+		for _, id := range req.GetCommentIds() {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			comment, err := q.GetCommentForUpdate(ctx, id)
+			if errors.Is(err, sql.ErrNoRows) {
+				resp.SkippedCommentIds = append(resp.SkippedCommentIds, id)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !transition.allowedFrom(comment.Status) {
+				resp.SkippedCommentIds = append(resp.SkippedCommentIds, id)
+				continue
+			}
+
+			err = q.UpdateCommentStatus(ctx, repository.UpdateCommentStatusParams{
+				ID:               comment.ID,
+				Status:           transition.to,
+				ModerationReason: req.GetReason().String(),
+				ModeratorID:      sql.NullInt64{Int64: caller.userID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			resp.ModeratedCommentIds = append(resp.ModeratedCommentIds, id)
+			reviewIDs = append(reviewIDs, comment.ReviewID)
+		}
+
+		refreshed := make(map[int64]bool, len(reviewIDs))
+		for _, reviewID := range reviewIDs {
+			if refreshed[reviewID] {
+				continue
+			}
+			refreshed[reviewID] = true
+			if err := q.RefreshCommentCount(ctx, reviewID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Message = localize(ctx, msgCommentsModerated, len(resp.ModeratedCommentIds))
+	return resp, nil
+}
+
+func validateCommentContent(content string) []fieldViolation {
+	var violations []fieldViolation
+	if strings.TrimSpace(content) == "" {
+		violations = append(violations, fieldViolation{field: "content", description: msgContentEmpty})
+	}
+	if utf8.RuneCountInString(content) > maxCommentLength {
+		violations = append(violations, fieldViolation{field: "content", description: msgContentLength, args: []interface{}{maxCommentLength}})
+	}
+	return violations
+}
+
+// lockPublishedReview locks a review for the caller's transaction. Reviews
+// which are not published are reported as not found.
+func lockPublishedReview(ctx context.Context, q *repository.Queries, id int64) (repository.Review, error) {
+	review, err := q.GetReviewForUpdate(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return review, errNotFound("review", id)
+	}
+	if err != nil {
+		return review, err
+	}
+	if review.Status != repository.ReviewStatusApproved {
+		return review, errNotFound("review", id)
+	}
+	return review, nil
+}
+
+// lockOwnComment locks a comment which the caller wrote, or can change as an
+// admin, along with its review.
+func lockOwnComment(ctx context.Context, q *repository.Queries, id int64, caller principal) (repository.ReviewComment, error) {
+	comment, err := lockComment(ctx, q, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return comment, errNotFound("comment", id)
+	}
+	if err != nil {
+		return comment, err
+	}
+	if comment.UserID != caller.userID && !caller.isAdmin() {
+		return comment, errForbidden("NOT_COMMENT_AUTHOR")
+	}
+	return comment, nil
+}
+
+// lockComment locks a comment after its review. Deleting a review deletes
+// its comments, so every transaction changing both locks the review first to
+// avoid deadlocks; the review also serializes the recounts of its comments.
+func lockComment(ctx context.Context, q *repository.Queries, id int64) (repository.ReviewComment, error) {
+	comment, err := q.GetComment(ctx, id)
+	if err != nil {
+		return comment, err
+	}
+	if _, err := q.GetReviewForUpdate(ctx, comment.ReviewID); err != nil {
+		return comment, err
+	}
+	// deleted in the meantime if the review was
+	return q.GetCommentForUpdate(ctx, id)
+}
+
+// lockCommentReviews locks the reviews of the comments, in id order so that
+// concurrent batches can't deadlock either. Missing comments are ignored.
+func lockCommentReviews(ctx context.Context, q *repository.Queries, commentIDs []int64) error {
+	var reviewIDs []int64
+	seen := make(map[int64]bool, len(commentIDs))
+	for _, id := range commentIDs {
+		comment, err := q.GetComment(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !seen[comment.ReviewID] {
+			seen[comment.ReviewID] = true
+			reviewIDs = append(reviewIDs, comment.ReviewID)
+		}
+	}
+	sort.Slice(reviewIDs, func(i, j int) bool { return reviewIDs[i] < reviewIDs[j] })
+	for _, id := range reviewIDs {
+		if _, err := q.GetReviewForUpdate(ctx, id); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+	return nil
+}
+
+func toPbComment(comment repository.ReviewComment) *pb.Comment {
+	return &pb.Comment{
+		CommentId: comment.ID,
+		ReviewId:  comment.ReviewID,
+		ParentId:  comment.ParentID.Int64,
+		UserId:    comment.UserID,
+		Content:   comment.Content,
+		Status:    toPbStatus(comment.Status),
+		CreatedAt: comment.CreatedAt.Unix(),
+		UpdatedAt: comment.UpdatedAt.Unix(),
+	}
+}
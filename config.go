@@ -0,0 +1,44 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// getEnv returns the environment variable key, or def when it is unset.
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// getEnvDuration parses the environment variable key as a time.Duration, e.g. "15s".
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid duration %s=%q, using %s", key, v, def)
+		return def
+	}
+	return d
+}
+
+// getEnvInt parses the environment variable key as an integer.
+func getEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid integer %s=%q, using %d", key, v, def)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,14 @@
+CREATE TABLE
+    IF NOT EXISTS idempotency_key (
+        "user_id" int8 NOT NULL,
+        "method" text NOT NULL,
+        "key" text NOT NULL,
+        "fingerprint" bytea NOT NULL,
+        "response" bytea,
+        "created_at" timestamptz NOT NULL DEFAULT(now()),
+        "expires_at" timestamptz NOT NULL,
+        PRIMARY KEY ("user_id", "method", "key")
+    );
+
+CREATE INDEX
+    IF NOT EXISTS idempotency_key_expires_at_idx ON idempotency_key ("expires_at");
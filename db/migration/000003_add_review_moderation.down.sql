@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS review_moderation_event;
+
+DROP INDEX IF EXISTS review_product_id_status_idx;
+
+DROP INDEX IF EXISTS review_status_id_idx;
+
+ALTER TABLE review
+DROP COLUMN IF EXISTS "status",
+DROP COLUMN IF EXISTS "moderation_reason",
+DROP COLUMN IF EXISTS "moderator_id",
+DROP COLUMN IF EXISTS "moderated_at",
+DROP COLUMN IF EXISTS "created_at";
+
+DROP TYPE IF EXISTS review_status;
@@ -0,0 +1,46 @@
+CREATE TYPE review_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected',
+    'hidden'
+);
+
+-- existing reviews were already public, new ones wait for moderation
+ALTER TABLE review
+ADD
+    COLUMN "status" review_status NOT NULL DEFAULT('approved'),
+ADD
+    COLUMN "moderation_reason" text NOT NULL DEFAULT(''),
+ADD
+    COLUMN "moderator_id" int8,
+ADD
+    COLUMN "moderated_at" timestamptz,
+ADD
+    COLUMN "created_at" timestamptz NOT NULL DEFAULT(now());
+
+ALTER TABLE review ALTER COLUMN "status" SET DEFAULT('pending');
+
+CREATE INDEX
+    IF NOT EXISTS review_status_id_idx ON review ("status", "id");
+
+CREATE INDEX
+    IF NOT EXISTS review_product_id_status_idx ON review ("product_id", "status");
+
+CREATE TABLE
+    IF NOT EXISTS review_moderation_event (
+        "id" serial8 PRIMARY KEY,
+        "review_id" int8 NOT NULL,
+        "from_status" review_status NOT NULL,
+        "to_status" review_status NOT NULL,
+        "reason" text NOT NULL DEFAULT(''),
+        "note" text NOT NULL DEFAULT(''),
+        "moderator_id" int8,
+        "created_at" timestamptz NOT NULL DEFAULT(now())
+    );
+
+ALTER TABLE review_moderation_event
+ADD
+    FOREIGN KEY ("review_id") REFERENCES review ("id") ON DELETE CASCADE;
+
+CREATE INDEX
+    IF NOT EXISTS review_moderation_event_review_id_idx ON review_moderation_event ("review_id", "id");
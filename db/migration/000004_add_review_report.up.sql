@@ -0,0 +1,14 @@
+CREATE TABLE
+    IF NOT EXISTS review_report (
+        "id" serial8 PRIMARY KEY,
+        "review_id" int8 NOT NULL,
+        "user_id" int8 NOT NULL,
+        "reason" text NOT NULL,
+        "comment" text NOT NULL DEFAULT(''),
+        "created_at" timestamptz NOT NULL DEFAULT(now()),
+        UNIQUE ("review_id", "user_id")
+    );
+
+ALTER TABLE review_report
+ADD
+    FOREIGN KEY ("review_id") REFERENCES review ("id") ON DELETE CASCADE;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS review_vote;
+
+DROP INDEX IF EXISTS review_product_id_helpful_count_idx;
+
+ALTER TABLE review
+DROP COLUMN IF EXISTS "helpful_count",
+DROP COLUMN IF EXISTS "not_helpful_count";
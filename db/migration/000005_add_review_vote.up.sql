@@ -0,0 +1,24 @@
+-- counters are maintained with the votes so reviews can be sorted by them
+ALTER TABLE review
+ADD
+    COLUMN "helpful_count" integer NOT NULL DEFAULT(0),
+ADD
+    COLUMN "not_helpful_count" integer NOT NULL DEFAULT(0);
+
+CREATE INDEX
+    IF NOT EXISTS review_product_id_helpful_count_idx ON review ("product_id", "helpful_count" DESC, "id" DESC)
+WHERE "status" = 'approved';
+
+CREATE TABLE
+    IF NOT EXISTS review_vote (
+        "review_id" int8 NOT NULL,
+        "user_id" int8 NOT NULL,
+        "helpful" boolean NOT NULL,
+        "created_at" timestamptz NOT NULL DEFAULT(now()),
+        "updated_at" timestamptz NOT NULL DEFAULT(now()),
+        PRIMARY KEY ("review_id", "user_id")
+    );
+
+ALTER TABLE review_vote
+ADD
+    FOREIGN KEY ("review_id") REFERENCES review ("id") ON DELETE CASCADE;
@@ -0,0 +1,12 @@
+CREATE TABLE
+    IF NOT EXISTS review_reply (
+        "review_id" int8 PRIMARY KEY,
+        "supplier_id" int8 NOT NULL,
+        "content" text NOT NULL,
+        "created_at" timestamptz NOT NULL DEFAULT(now()),
+        "updated_at" timestamptz NOT NULL DEFAULT(now())
+    );
+
+ALTER TABLE review_reply
+ADD
+    FOREIGN KEY ("review_id") REFERENCES review ("id") ON DELETE CASCADE;
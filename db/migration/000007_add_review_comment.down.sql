@@ -0,0 +1,3 @@
+ALTER TABLE review DROP COLUMN IF EXISTS "comment_count";
+
+DROP TABLE IF EXISTS review_comment;
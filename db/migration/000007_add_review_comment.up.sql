@@ -0,0 +1,38 @@
+-- comments share the moderation states of reviews, only approved ones are
+-- public and counted
+CREATE TABLE
+    IF NOT EXISTS review_comment (
+        "id" serial8 PRIMARY KEY,
+        "review_id" int8 NOT NULL,
+        "parent_id" int8,
+        "user_id" int8 NOT NULL,
+        "content" text NOT NULL,
+        "status" review_status NOT NULL DEFAULT('pending'),
+        "moderation_reason" text NOT NULL DEFAULT(''),
+        "moderator_id" int8,
+        "moderated_at" timestamptz,
+        "created_at" timestamptz NOT NULL DEFAULT(now()),
+        "updated_at" timestamptz NOT NULL DEFAULT(now())
+    );
+
+ALTER TABLE review_comment
+ADD
+    FOREIGN KEY ("review_id") REFERENCES review ("id") ON DELETE CASCADE;
+
+ALTER TABLE review_comment
+ADD
+    FOREIGN KEY ("parent_id") REFERENCES review_comment ("id") ON DELETE CASCADE;
+
+CREATE INDEX
+    IF NOT EXISTS review_comment_review_id_idx ON review_comment ("review_id", "id")
+WHERE "parent_id" IS NULL;
+
+CREATE INDEX
+    IF NOT EXISTS review_comment_parent_id_idx ON review_comment ("parent_id", "id");
+
+CREATE INDEX
+    IF NOT EXISTS review_comment_status_id_idx ON review_comment ("status", "id");
+
+ALTER TABLE review
+ADD
+    COLUMN "comment_count" integer NOT NULL DEFAULT(0);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS answer_upvote;
+
+DROP TABLE IF EXISTS question_answer;
+
+DROP TABLE IF EXISTS product_question;
@@ -0,0 +1,44 @@
+CREATE TABLE
+    IF NOT EXISTS product_question (
+        "id" serial8 PRIMARY KEY,
+        "product_id" int8 NOT NULL,
+        "user_id" int8 NOT NULL,
+        "content" text NOT NULL,
+        "answer_count" integer NOT NULL DEFAULT(0),
+        "created_at" timestamptz NOT NULL DEFAULT(now())
+    );
+
+CREATE INDEX
+    IF NOT EXISTS product_question_product_id_idx ON product_question ("product_id", "id");
+
+-- badges are resolved from order service when the answer is posted
+CREATE TABLE
+    IF NOT EXISTS question_answer (
+        "id" serial8 PRIMARY KEY,
+        "question_id" int8 NOT NULL,
+        "user_id" int8 NOT NULL,
+        "content" text NOT NULL,
+        "verified_buyer" boolean NOT NULL DEFAULT(false),
+        "from_supplier" boolean NOT NULL DEFAULT(false),
+        "upvote_count" integer NOT NULL DEFAULT(0),
+        "created_at" timestamptz NOT NULL DEFAULT(now())
+    );
+
+ALTER TABLE question_answer
+ADD
+    FOREIGN KEY ("question_id") REFERENCES product_question ("id") ON DELETE CASCADE;
+
+CREATE INDEX
+    IF NOT EXISTS question_answer_question_id_idx ON question_answer ("question_id");
+
+CREATE TABLE
+    IF NOT EXISTS answer_upvote (
+        "answer_id" int8 NOT NULL,
+        "user_id" int8 NOT NULL,
+        "created_at" timestamptz NOT NULL DEFAULT(now()),
+        PRIMARY KEY ("answer_id", "user_id")
+    );
+
+ALTER TABLE answer_upvote
+ADD
+    FOREIGN KEY ("answer_id") REFERENCES question_answer ("id") ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+CREATE TABLE
+    IF NOT EXISTS review_sub_rating (
+        "review_id" int8 NOT NULL,
+        "dimension" text NOT NULL,
+        "rating" integer NOT NULL CHECK ("rating" BETWEEN 1 AND 5),
+        PRIMARY KEY ("review_id", "dimension")
+    );
+
+ALTER TABLE review_sub_rating
+ADD
+    FOREIGN KEY ("review_id") REFERENCES review ("id") ON DELETE CASCADE;
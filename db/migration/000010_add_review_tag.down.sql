@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS review_tag;
+
+DROP TABLE IF EXISTS tag;
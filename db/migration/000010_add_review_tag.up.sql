@@ -0,0 +1,27 @@
+CREATE TABLE
+    IF NOT EXISTS tag (
+        "id" serial8 PRIMARY KEY,
+        "name" text NOT NULL UNIQUE,
+        "sentiment" text NOT NULL DEFAULT('neutral'),
+        -- retired tags stay on past reviews but can't be picked anymore
+        "active" boolean NOT NULL DEFAULT(true),
+        "created_at" timestamptz NOT NULL DEFAULT(now())
+    );
+
+CREATE TABLE
+    IF NOT EXISTS review_tag (
+        "review_id" int8 NOT NULL,
+        "tag_id" int8 NOT NULL,
+        PRIMARY KEY ("review_id", "tag_id")
+    );
+
+ALTER TABLE review_tag
+ADD
+    FOREIGN KEY ("review_id") REFERENCES review ("id") ON DELETE CASCADE;
+
+ALTER TABLE review_tag
+ADD
+    FOREIGN KEY ("tag_id") REFERENCES tag ("id") ON DELETE CASCADE;
+
+CREATE INDEX
+    IF NOT EXISTS review_tag_tag_id_idx ON review_tag ("tag_id", "review_id");
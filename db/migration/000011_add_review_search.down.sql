@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS review_search_vector_idx;
+
+ALTER TABLE review DROP COLUMN IF EXISTS "search_vector";
+
+DROP TEXT SEARCH CONFIGURATION IF EXISTS public.vn_unaccent;
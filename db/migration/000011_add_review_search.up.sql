@@ -0,0 +1,20 @@
+-- needs a role allowed to create the extension
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- the simple parser with diacritics folded, so "giao hang" matches
+-- "giao hàng" while headlines are still cut from the original text
+CREATE TEXT SEARCH CONFIGURATION public.vn_unaccent (COPY = simple);
+
+ALTER TEXT SEARCH CONFIGURATION public.vn_unaccent
+ALTER MAPPING FOR
+    hword,
+    hword_part,
+    word
+WITH unaccent, simple;
+
+ALTER TABLE review
+ADD
+    COLUMN "search_vector" tsvector GENERATED ALWAYS AS (to_tsvector('public.vn_unaccent', "content")) STORED;
+
+CREATE INDEX
+    IF NOT EXISTS review_search_vector_idx ON review USING GIN ("search_vector");
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS review_user_id_idx;
@@ -0,0 +1,2 @@
+CREATE INDEX
+    IF NOT EXISTS review_user_id_idx ON review ("user_id", "id");
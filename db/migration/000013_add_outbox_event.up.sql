@@ -0,0 +1,18 @@
+-- events are written with the review change and delivered by the relay
+CREATE TABLE
+    IF NOT EXISTS outbox_event (
+        "id" serial8 PRIMARY KEY,
+        "aggregate_id" int8 NOT NULL,
+        "event_type" text NOT NULL,
+        "payload" jsonb NOT NULL,
+        "created_at" timestamptz NOT NULL DEFAULT(now()),
+        "published_at" timestamptz
+    );
+
+CREATE INDEX
+    IF NOT EXISTS outbox_event_unpublished_idx ON outbox_event ("id")
+WHERE "published_at" IS NULL;
+
+CREATE INDEX
+    IF NOT EXISTS outbox_event_published_at_idx ON outbox_event ("published_at")
+WHERE "published_at" IS NOT NULL;
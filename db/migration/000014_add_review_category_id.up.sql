@@ -0,0 +1,4 @@
+-- category the sub-ratings were validated against, as sent by the client
+ALTER TABLE review
+ADD
+    COLUMN "category_id" int8 NOT NULL DEFAULT(0);
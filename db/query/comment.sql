@@ -0,0 +1,77 @@
+-- name: InsertComment :one
+INSERT INTO
+    review_comment (
+        "review_id",
+        "parent_id",
+        "user_id",
+        "content",
+        "status",
+        "moderation_reason"
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetComment :one
+SELECT * FROM review_comment
+WHERE "id" = $1;
+
+-- name: GetCommentForUpdate :one
+SELECT * FROM review_comment
+WHERE "id" = $1
+FOR UPDATE;
+
+-- name: UpdateCommentContent :one
+UPDATE review_comment
+SET
+    "content" = $2,
+    "status" = $3,
+    "moderation_reason" = $4,
+    "updated_at" = now()
+WHERE "id" = $1
+RETURNING *;
+
+-- name: UpdateCommentStatus :exec
+UPDATE review_comment
+SET
+    "status" = $2,
+    "moderation_reason" = $3,
+    "moderator_id" = $4,
+    "moderated_at" = now()
+WHERE "id" = $1;
+
+-- name: DeleteComment :exec
+DELETE FROM review_comment WHERE "id" = $1;
+
+-- name: ListTopLevelComments :many
+SELECT * FROM review_comment
+WHERE
+    "review_id" = $1
+    AND "parent_id" IS NULL
+    AND "status" = 'approved'
+    AND "id" > sqlc.arg(after_id)
+ORDER BY "id"
+LIMIT $2;
+
+-- name: ListCommentReplies :many
+SELECT * FROM review_comment
+WHERE
+    "parent_id" = ANY(sqlc.arg(parent_ids)::int8[])
+    AND "status" = 'approved'
+ORDER BY "parent_id", "id";
+
+-- name: ListCommentsByStatus :many
+SELECT * FROM review_comment
+WHERE "status" = $1 AND "id" > sqlc.arg(after_id)
+ORDER BY "id"
+LIMIT $2;
+
+-- name: RefreshCommentCount :exec
+UPDATE review
+SET "comment_count" = (
+        SELECT count(*)
+        FROM review_comment
+        WHERE
+            review_comment."review_id" = review."id"
+            AND review_comment."status" = 'approved'
+    )
+WHERE review."id" = $1;
@@ -0,0 +1,39 @@
+-- name: ClaimIdempotencyKey :execrows
+-- takes over expired keys and keys whose first request never completed
+
+INSERT INTO
+    idempotency_key (
+        "user_id",
+        "method",
+        "key",
+        "fingerprint",
+        "expires_at"
+    )
+VALUES ($1, $2, $3, $4, $5) ON CONFLICT ("user_id", "method", "key") DO
+UPDATE
+SET
+    "fingerprint" = EXCLUDED."fingerprint",
+    "response" = NULL,
+    "created_at" = now(),
+    "expires_at" = EXCLUDED."expires_at"
+WHERE
+    idempotency_key."expires_at" < now()
+    OR (
+        idempotency_key."response" IS NULL
+        AND idempotency_key."created_at" < sqlc.arg(stale_before)
+    );
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_key
+WHERE "user_id" = $1 AND "method" = $2 AND "key" = $3;
+
+-- name: SaveIdempotentResponse :exec
+UPDATE idempotency_key SET "response" = $4
+WHERE "user_id" = $1 AND "method" = $2 AND "key" = $3;
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_key
+WHERE "user_id" = $1 AND "method" = $2 AND "key" = $3 AND "response" IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_key WHERE "expires_at" < now();
@@ -0,0 +1,41 @@
+-- name: ListReviewsAwaitingModeration :many
+-- pending reviews, and reviews hidden by reports which no moderator decided on
+SELECT * FROM review
+WHERE (
+        "status" = 'pending'
+        OR ("status" = 'hidden' AND "moderator_id" IS NULL)
+    )
+    AND "id" > sqlc.arg(after_id)
+ORDER BY "id"
+LIMIT $1;
+
+-- name: GetReviewForUpdate :one
+SELECT * FROM review
+WHERE "id" = $1
+FOR UPDATE;
+
+-- name: UpdateReviewStatus :exec
+UPDATE review
+SET
+    "status" = $2,
+    "moderation_reason" = $3,
+    "moderator_id" = $4,
+    "moderated_at" = now()
+WHERE "id" = $1;
+
+-- name: InsertModerationEvent :exec
+INSERT INTO
+    review_moderation_event (
+        "review_id",
+        "from_status",
+        "to_status",
+        "reason",
+        "note",
+        "moderator_id"
+    )
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListModerationEvents :many
+SELECT * FROM review_moderation_event
+WHERE "review_id" = $1
+ORDER BY "id";
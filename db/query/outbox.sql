@@ -0,0 +1,29 @@
+-- name: InsertOutboxEvent :exec
+INSERT INTO
+    outbox_event (
+        "aggregate_id",
+        "event_type",
+        "payload"
+    )
+VALUES ($1, $2, $3);
+
+-- name: ClaimOutboxEvents :many
+-- relays running in other replicas skip the events locked here
+SELECT * FROM outbox_event
+WHERE "published_at" IS NULL
+ORDER BY "id"
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox_event
+SET "published_at" = now()
+WHERE "id" = ANY(sqlc.arg(ids)::int8[]);
+
+-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox_event
+WHERE "published_at" < sqlc.arg(published_before)::timestamptz;
+
+-- name: NotifyReviewEvent :exec
+-- delivered to listeners of every replica once the transaction commits
+SELECT pg_notify('review_events', sqlc.arg(payload)::text);
@@ -0,0 +1,61 @@
+-- name: InsertQuestion :one
+INSERT INTO
+    product_question (
+        "product_id",
+        "user_id",
+        "content"
+    )
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetQuestion :one
+SELECT * FROM product_question
+WHERE "id" = $1;
+
+-- name: ListQuestionsByProductID :many
+SELECT * FROM product_question
+WHERE "product_id" = $1 AND "id" > sqlc.arg(after_id)
+ORDER BY "id"
+LIMIT $2;
+
+-- name: IncrementAnswerCount :exec
+UPDATE product_question
+SET "answer_count" = "answer_count" + 1
+WHERE "id" = $1;
+
+-- name: InsertAnswer :one
+INSERT INTO
+    question_answer (
+        "question_id",
+        "user_id",
+        "content",
+        "verified_buyer",
+        "from_supplier"
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetAnswer :one
+SELECT * FROM question_answer
+WHERE "id" = $1;
+
+-- name: ListAnswersByQuestionIDs :many
+SELECT * FROM question_answer
+WHERE "question_id" = ANY(sqlc.arg(question_ids)::int8[])
+ORDER BY
+    "question_id",
+    "from_supplier" DESC,
+    "upvote_count" DESC,
+    "id";
+
+-- name: InsertAnswerUpvote :execrows
+INSERT INTO
+    answer_upvote ("answer_id", "user_id")
+VALUES ($1, $2)
+ON CONFLICT ("answer_id", "user_id") DO NOTHING;
+
+-- name: IncrementUpvoteCount :one
+UPDATE question_answer
+SET "upvote_count" = "upvote_count" + 1
+WHERE "id" = $1
+RETURNING "upvote_count";
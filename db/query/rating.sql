@@ -0,0 +1,33 @@
+-- name: InsertSubRating :exec
+INSERT INTO
+    review_sub_rating (
+        "review_id",
+        "dimension",
+        "rating"
+    )
+VALUES ($1, $2, $3);
+
+-- name: GetSubRatingsByReviewIDs :many
+SELECT * FROM review_sub_rating
+WHERE "review_id" = ANY(sqlc.arg(review_ids)::int8[])
+ORDER BY "review_id", "dimension";
+
+-- name: GetProductRating :one
+SELECT
+    count(*) AS "review_count",
+    coalesce(avg("num_star"), 0)::float8 AS "average_star"
+FROM review
+WHERE "product_id" = $1 AND "status" = 'approved';
+
+-- name: GetProductSubRatings :many
+SELECT
+    review_sub_rating."dimension",
+    count(*) AS "rating_count",
+    avg(review_sub_rating."rating")::float8 AS "average_rating"
+FROM review_sub_rating
+    INNER JOIN review ON review."id" = review_sub_rating."review_id"
+WHERE
+    review."product_id" = $1
+    AND review."status" = 'approved'
+GROUP BY review_sub_rating."dimension"
+ORDER BY review_sub_rating."dimension";
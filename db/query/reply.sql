@@ -0,0 +1,20 @@
+-- name: UpsertReviewReply :one
+INSERT INTO
+    review_reply (
+        "review_id",
+        "supplier_id",
+        "content"
+    )
+VALUES ($1, $2, $3)
+ON CONFLICT ("review_id") DO
+UPDATE
+SET
+    "content" = excluded."content",
+    "updated_at" = now()
+WHERE
+    review_reply."supplier_id" = excluded."supplier_id"
+RETURNING *;
+
+-- name: GetRepliesByReviewIDs :many
+SELECT * FROM review_reply
+WHERE "review_id" = ANY(sqlc.arg(review_ids)::int8[]);
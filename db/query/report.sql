@@ -0,0 +1,30 @@
+-- name: InsertReviewReport :execrows
+INSERT INTO
+    review_report (
+        "review_id",
+        "user_id",
+        "reason",
+        "comment"
+    )
+VALUES ($1, $2, $3, $4)
+ON CONFLICT ("review_id", "user_id") DO NOTHING;
+
+-- name: CountReportsSince :one
+SELECT count(*) FROM review_report
+WHERE "review_id" = $1 AND "created_at" > sqlc.arg(since);
+
+-- name: ListReportedReviews :many
+SELECT
+    "review_id",
+    count(*) AS "report_count",
+    max("created_at")::timestamptz AS "last_reported_at"
+FROM review_report
+WHERE "review_id" > sqlc.arg(after_id)
+GROUP BY "review_id"
+ORDER BY "review_id"
+LIMIT $1;
+
+-- name: ListReportsByReviewIDs :many
+SELECT * FROM review_report
+WHERE "review_id" = ANY(sqlc.arg(review_ids)::int8[])
+ORDER BY "review_id", "id";
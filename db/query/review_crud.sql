@@ -5,35 +5,88 @@ INSERT INTO
         "user_id",
         "product_id",
         "num_star",
-        "content"
+        "content",
+        "status",
+        "moderation_reason",
+        "category_id"
     )
-VALUES ($1, $2, $3, $4) RETURNING  *;
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING  *;
 
 -- name: InsertImage :exec
 
 INSERT INTO image ("review_id", "image_url") VALUES ($1, $2) ;
 
--- name: SelectReviewByProductID :many
-
-SELECT
-    review.id,
-    "user_id",
-    "product_id",
-    "num_star",
-    "image_url",
-    "content"
-FROM review
-    INNER JOIN image ON review.id = image.review_id
-WHERE review.id = $1;
-
 -- name: DeleteReview :exec
 
 DELETE FROM review WHERE id = $1;
 
 -- name: GetAllReviewByProductID :many
+-- reviews must carry every tag in tag_ids, which may be empty
+SELECT * FROM review
+WHERE
+    "product_id" = $1
+    AND "status" = 'approved'
+    AND (
+        cardinality(sqlc.arg(tag_ids)::int8[]) = 0
+        OR "id" IN (
+            SELECT "review_id"
+            FROM review_tag
+            WHERE "tag_id" = ANY(sqlc.arg(tag_ids)::int8[])
+            GROUP BY "review_id"
+            HAVING count(*) = cardinality(sqlc.arg(tag_ids)::int8[])
+        )
+    )
+ORDER BY "id" DESC;
+
+-- name: GetMostHelpfulReviewsByProductID :many
 SELECT * FROM review
-WHERE "product_id" = $1;
+WHERE
+    "product_id" = $1
+    AND "status" = 'approved'
+    AND (
+        cardinality(sqlc.arg(tag_ids)::int8[]) = 0
+        OR "id" IN (
+            SELECT "review_id"
+            FROM review_tag
+            WHERE "tag_id" = ANY(sqlc.arg(tag_ids)::int8[])
+            GROUP BY "review_id"
+            HAVING count(*) = cardinality(sqlc.arg(tag_ids)::int8[])
+        )
+    )
+ORDER BY "helpful_count" DESC, "id" DESC;
 
 -- name: GetImagesByOrderID :many
 SELECT "image_url" FROM "image"
 WHERE "review_id" = $1;
+
+-- name: GetReviewByID :one
+SELECT * FROM review
+WHERE "id" = $1;
+
+-- name: UpdateReviewContent :one
+UPDATE review SET "content" = $2
+WHERE "id" = $1
+RETURNING *;
+
+-- name: GetReviewsByIDs :many
+SELECT * FROM review
+WHERE "id" = ANY(sqlc.arg(ids)::int8[])
+ORDER BY "id";
+
+-- name: ListReviewsByUser :many
+-- newest first, resuming before before_id unless it is 0
+SELECT * FROM review
+WHERE
+    "user_id" = $1
+    AND (
+        sqlc.arg(before_id)::int8 = 0
+        OR "id" < sqlc.arg(before_id)
+    )
+ORDER BY "id" DESC
+LIMIT $2;
+
+-- name: GetReviewedProductIDs :many
+SELECT DISTINCT "product_id" FROM review
+WHERE
+    "user_id" = $1
+    AND "product_id" = ANY(sqlc.arg(product_ids)::int8[]);
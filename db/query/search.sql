@@ -0,0 +1,40 @@
+-- name: SearchReviews :many
+-- results are ranked, then ordered by id, and resume after the
+-- (after_rank, after_id) cursor unless after_id is 0
+SELECT
+    review."id",
+    review."user_id",
+    review."product_id",
+    review."num_star",
+    review."content",
+    review."status",
+    review."helpful_count",
+    review."not_helpful_count",
+    review."comment_count",
+    review."created_at",
+    ts_rank(review."search_vector", query)::float4 AS "rank",
+    -- matches are delimited with control characters, the snippet is escaped
+    -- before they are turned into markup
+    ts_headline(
+        'public.vn_unaccent',
+        review."content",
+        query,
+        'StartSel="' || chr(2) || '", StopSel="' || chr(3) || '", MaxFragments=2, MaxWords=30, MinWords=10'
+    )::text AS "snippet"
+FROM
+    review,
+    websearch_to_tsquery('public.vn_unaccent', sqlc.arg(query)) AS query
+WHERE
+    review."search_vector" @@ query
+    AND review."status" = 'approved'
+    AND (
+        sqlc.arg(product_id)::int8 = 0
+        OR review."product_id" = sqlc.arg(product_id)
+    )
+    AND review."num_star" BETWEEN sqlc.arg(min_star) AND sqlc.arg(max_star)
+    AND (
+        sqlc.arg(after_id)::int8 = 0
+        OR (ts_rank(review."search_vector", query)::float4, review."id") < (sqlc.arg(after_rank)::float4, sqlc.arg(after_id)::int8)
+    )
+ORDER BY "rank" DESC, review."id" DESC
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,52 @@
+-- name: InsertTag :one
+INSERT INTO tag ("name", "sentiment")
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: UpdateTag :one
+-- null arguments keep the current value
+UPDATE tag
+SET
+    "name" = COALESCE(sqlc.narg(name), "name"),
+    "sentiment" = COALESCE(sqlc.narg(sentiment), "sentiment"),
+    "active" = COALESCE(sqlc.narg(active), "active")
+WHERE "id" = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListTags :many
+SELECT * FROM tag
+WHERE "active" OR sqlc.arg(include_inactive)::boolean
+ORDER BY "id";
+
+-- name: GetActiveTagsByIDs :many
+SELECT * FROM tag
+WHERE "id" = ANY(sqlc.arg(ids)::int8[]) AND "active";
+
+-- name: InsertReviewTag :exec
+INSERT INTO review_tag ("review_id", "tag_id") VALUES ($1, $2);
+
+-- name: GetTagsByReviewIDs :many
+SELECT
+    review_tag."review_id",
+    tag.*
+FROM review_tag
+    INNER JOIN tag ON tag."id" = review_tag."tag_id"
+WHERE
+    review_tag."review_id" = ANY(sqlc.arg(review_ids)::int8[])
+ORDER BY
+    review_tag."review_id",
+    tag."id";
+
+-- name: GetProductTagFrequencies :many
+SELECT
+    tag.*,
+    count(*) AS "review_count"
+FROM review_tag
+    INNER JOIN review ON review."id" = review_tag."review_id"
+    INNER JOIN tag ON tag."id" = review_tag."tag_id"
+WHERE
+    review."product_id" = $1
+    AND review."status" = 'approved'
+    AND tag."active"
+GROUP BY tag."id"
+ORDER BY "review_count" DESC, tag."id";
@@ -0,0 +1,25 @@
+-- name: GetReviewVote :one
+SELECT "helpful" FROM review_vote
+WHERE "review_id" = $1 AND "user_id" = $2;
+
+-- name: UpsertReviewVote :exec
+INSERT INTO
+    review_vote ("review_id", "user_id", "helpful")
+VALUES ($1, $2, $3)
+ON CONFLICT ("review_id", "user_id") DO
+UPDATE
+SET
+    "helpful" = excluded."helpful",
+    "updated_at" = now();
+
+-- name: DeleteReviewVote :exec
+DELETE FROM review_vote
+WHERE "review_id" = $1 AND "user_id" = $2;
+
+-- name: AddReviewVoteCounts :one
+UPDATE review
+SET
+    "helpful_count" = "helpful_count" + sqlc.arg(helpful_delta),
+    "not_helpful_count" = "not_helpful_count" + sqlc.arg(not_helpful_delta)
+WHERE "id" = $1
+RETURNING "helpful_count", "not_helpful_count";
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/screening"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain is reported in the ErrorInfo detail of every domain error.
+const errorDomain = "review-service"
+
+// errorKind classifies domain errors independently of the transport.
+type errorKind int
+
+const (
+	kindInvalidArgument errorKind = iota + 1
+	kindNotFound
+	kindNotPurchased
+	kindForbidden
+	kindDependencyUnavailable
+	kindRateLimited
+	kindConflict
+	kindAlreadyExists
+	kindUnavailable
+	kindUnauthenticated
+)
+
+var kindCodes = map[errorKind]codes.Code{
+	kindInvalidArgument:       codes.InvalidArgument,
+	kindNotFound:              codes.NotFound,
+	kindNotPurchased:          codes.FailedPrecondition,
+	kindForbidden:             codes.PermissionDenied,
+	kindDependencyUnavailable: codes.Unavailable,
+	kindRateLimited:           codes.ResourceExhausted,
+	kindConflict:              codes.Aborted,
+	kindAlreadyExists:         codes.AlreadyExists,
+	kindUnavailable:           codes.Unavailable,
+	kindUnauthenticated:       codes.Unauthenticated,
+}
+
+// fieldViolation describes why a single request field is invalid.
+type fieldViolation struct {
+	field       string
+	description messageID
+	args        []interface{}
+}
+
+// reviewError is the error type returned by handlers. It is translated into
+// a gRPC status by errorUnaryInterceptor; the cause is logged but never sent
+// to the client.
+type reviewError struct {
+	kind       errorKind
+	reason     string
+	message    messageID
+	metadata   map[string]string
+	violations []fieldViolation
+	retryAfter time.Duration
+	cause      error
+}
+
+func (e *reviewError) Error() string {
+	msg := translate(localeEn, e.message)
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", msg, e.cause)
+	}
+	return msg
+}
+
+func (e *reviewError) Unwrap() error {
+	return e.cause
+}
+
+// status converts the error into a gRPC status in the given locale, with
+// ErrorInfo and LocalizedMessage details, plus BadRequest for invalid
+// arguments and RetryInfo when the client should back off.
+func (e *reviewError) status(locale string) *status.Status {
+	message := translate(locale, e.message)
+	details := []proto.Message{
+		&errdetails.ErrorInfo{
+			Reason:   e.reason,
+			Domain:   errorDomain,
+			Metadata: e.metadata,
+		},
+		&errdetails.LocalizedMessage{
+			Locale:  locale,
+			Message: message,
+		},
+	}
+	if len(e.violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range e.violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.field,
+				Description: translate(locale, v.description, v.args...),
+			})
+		}
+		details = append(details, badRequest)
+	}
+	if e.retryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(e.retryAfter),
+		})
+	}
+
+	st := status.New(kindCodes[e.kind], message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+func errInvalidArgument(violations ...fieldViolation) error {
+	return &reviewError{
+		kind:       kindInvalidArgument,
+		reason:     "INVALID_ARGUMENT",
+		message:    msgInvalidArgument,
+		violations: violations,
+	}
+}
+
+func errNotFound(resource string, id int64) error {
+	return &reviewError{
+		kind:     kindNotFound,
+		reason:   "NOT_FOUND",
+		message:  msgNotFound,
+		metadata: map[string]string{"resource": resource, "id": fmt.Sprint(id)},
+	}
+}
+
+func errNotPurchased(productID int64) error {
+	return &reviewError{
+		kind:     kindNotPurchased,
+		reason:   "PRODUCT_NOT_PURCHASED",
+		message:  msgNotPurchased,
+		metadata: map[string]string{"product_id": fmt.Sprint(productID)},
+	}
+}
+
+// errUnauthenticated is returned when the caller's credentials are missing
+// or invalid. The cause is logged, never sent to the caller.
+func errUnauthenticated(reason string, cause error) error {
+	return &reviewError{
+		kind:    kindUnauthenticated,
+		reason:  reason,
+		message: msgUnauthenticated,
+		cause:   cause,
+	}
+}
+
+func errForbidden(reason string) error {
+	return &reviewError{
+		kind:    kindForbidden,
+		reason:  reason,
+		message: msgForbidden,
+	}
+}
+
+func errDependencyUnavailable(dependency string, cause error) error {
+	return &reviewError{
+		kind:     kindDependencyUnavailable,
+		reason:   "DEPENDENCY_UNAVAILABLE",
+		message:  msgDependencyUnavailable,
+		metadata: map[string]string{"dependency": dependency},
+		cause:    cause,
+	}
+}
+
+func errRateLimited(retryAfter time.Duration) error {
+	return &reviewError{
+		kind:       kindRateLimited,
+		reason:     "RATE_LIMITED",
+		message:    msgRateLimited,
+		retryAfter: retryAfter,
+	}
+}
+
+// errSlowConsumer ends the streams of watchers which fell too far behind.
+func errSlowConsumer() error {
+	return &reviewError{
+		kind:    kindRateLimited,
+		reason:  "SLOW_CONSUMER",
+		message: msgSlowConsumer,
+	}
+}
+
+// errShuttingDown ends streams when the server stops, clients reconnect to
+// another replica.
+func errShuttingDown() error {
+	return &reviewError{
+		kind:    kindUnavailable,
+		reason:  "SHUTTING_DOWN",
+		message: msgShuttingDown,
+	}
+}
+
+func errAlreadyReported(reviewID int64) error {
+	return &reviewError{
+		kind:     kindAlreadyExists,
+		reason:   "ALREADY_REPORTED",
+		message:  msgAlreadyReported,
+		metadata: map[string]string{"review_id": fmt.Sprint(reviewID)},
+	}
+}
+
+func errTagExists(name string) error {
+	return &reviewError{
+		kind:     kindAlreadyExists,
+		reason:   "TAG_EXISTS",
+		message:  msgTagExists,
+		metadata: map[string]string{"name": name},
+	}
+}
+
+// screeningMessages describe why the screening pipeline rejected content.
+var screeningMessages = map[string]messageID{
+	screening.ReasonBannedWord: msgContentBannedWord,
+	screening.ReasonURL:        msgContentContactInfo,
+	screening.ReasonEmail:      msgContentContactInfo,
+	screening.ReasonPhone:      msgContentContactInfo,
+	screening.ReasonShouting:   msgContentShouting,
+	screening.ReasonRepetition: msgContentRepetition,
+	screening.ReasonTooShort:   msgContentTooShort,
+}
+
+func errContentRejected(field, reason string) error {
+	description, ok := screeningMessages[reason]
+	if !ok {
+		description = msgContentRejected
+	}
+	return &reviewError{
+		kind:       kindInvalidArgument,
+		reason:     "CONTENT_REJECTED",
+		message:    msgContentRejected,
+		metadata:   map[string]string{"screening_reason": reason},
+		violations: []fieldViolation{{field: field, description: description}},
+	}
+}
+
+// errIdempotencyConflict is returned while another request with the same
+// idempotency key is being processed.
+func errIdempotencyConflict() error {
+	return &reviewError{
+		kind:    kindConflict,
+		reason:  "IDEMPOTENCY_KEY_IN_USE",
+		message: msgIdempotencyInProgress,
+	}
+}
+
+// fromDependency classifies an error returned by a call to another service.
+// Failures of the dependency itself become errDependencyUnavailable, while
+// statuses caused by the request (e.g. an expired token) are passed through.
+func fromDependency(dependency string, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return errDependencyUnavailable(dependency, err)
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown,
+		codes.ResourceExhausted, codes.Unimplemented, codes.DataLoss:
+		return errDependencyUnavailable(dependency, err)
+	default:
+		return err
+	}
+}
+
+// toStatusError translates an error returned by a handler into a gRPC status
+// error in the caller's locale. Unexpected errors are logged and replaced with
+// a generic Internal status so that SQL text and other internals never reach
+// the client.
+func toStatusError(ctx context.Context, method string, err error) error {
+	locale := localeFromContext(ctx)
+	var re *reviewError
+	if errors.As(err, &re) {
+		if re.cause != nil {
+			log.Printf("%s: %v", method, err)
+		}
+		return re.status(locale).Err()
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, translate(locale, msgNotFound))
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		return err
+	}
+	log.Printf("%s: unexpected error: %v", method, err)
+	return status.Error(codes.Internal, translate(locale, msgInternal))
+}
+
+// errorUnaryInterceptor translates handler errors into gRPC status errors.
+func errorUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatusError(ctx, info.FullMethod, err)
+	}
+	return resp, nil
+}
+
+// errorStreamInterceptor is the streaming counterpart of errorUnaryInterceptor.
+func errorStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return toStatusError(ss.Context(), info.FullMethod, err)
+	}
+	return nil
+}
@@ -13,6 +13,6 @@ require (
 	github.com/lib/pq v1.10.7
 	golang.org/x/net v0.4.0 // indirect
 	golang.org/x/sys v0.3.0 // indirect
-	golang.org/x/text v0.5.0 // indirect
-	google.golang.org/genproto v0.0.0-20221118155620-16455021b5e6 // indirect
+	golang.org/x/text v0.5.0
+	google.golang.org/genproto v0.0.0-20221118155620-16455021b5e6
 )
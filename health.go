@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// livenessService is reported SERVING for as long as the process answers rpcs,
+	// independently of its dependencies.
+	livenessService = "liveness"
+	// reviewServiceName is the fully qualified name of the review service.
+	reviewServiceName = "ecommerce.ReviewService"
+)
+
+// dependency is something the review service needs to serve traffic.
+type dependency struct {
+	name string
+	// critical dependencies take the whole service out of rotation when they fail
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// healthChecker periodically checks the dependencies and publishes their
+// status through the standard grpc.health.v1 service. Each dependency is
+// reported under its own name, the overall readiness under "" and
+// reviewServiceName.
+type healthChecker struct {
+	server   *health.Server
+	deps     []dependency
+	interval time.Duration
+	timeout  time.Duration
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+}
+
+func newHealthChecker(server *health.Server, interval, timeout time.Duration, deps ...dependency) *healthChecker {
+	server.SetServingStatus(livenessService, healthpb.HealthCheckResponse_SERVING)
+	return &healthChecker{
+		server:   server,
+		deps:     deps,
+		interval: interval,
+		timeout:  timeout,
+		statuses: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+	}
+}
+
+// run checks the dependencies every interval until ctx is done.
+func (hc *healthChecker) run(ctx context.Context) {
+	ticker := time.NewTicker(hc.interval)
+	defer ticker.Stop()
+	for {
+		hc.checkAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (hc *healthChecker) checkAll(ctx context.Context) {
+	ready := healthpb.HealthCheckResponse_SERVING
+	for _, dep := range hc.deps {
+		checkCtx, cancel := context.WithTimeout(ctx, hc.timeout)
+		err := dep.check(checkCtx)
+		cancel()
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			if dep.critical {
+				ready = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+		hc.setStatus(dep.name, status, err)
+	}
+	hc.setStatus("", ready, nil)
+	hc.setStatus(reviewServiceName, ready, nil)
+}
+
+func (hc *healthChecker) setStatus(service string, status healthpb.HealthCheckResponse_ServingStatus, err error) {
+	if prev, ok := hc.statuses[service]; !ok || prev != status {
+		if err != nil {
+			log.Printf("health: %q is %s: %v", service, status, err)
+		} else {
+			log.Printf("health: %q is %s", service, status)
+		}
+	}
+	hc.statuses[service] = status
+	hc.server.SetServingStatus(service, status)
+}
+
+// pingDB checks that the database accepts connections.
+func pingDB(db *sql.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// connState checks the connectivity state of a dialed client connection.
+// Idle connections are asked to connect so that a dependency nobody has
+// called yet still gets probed.
+func connState(conn *grpc.ClientConn) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		switch state := conn.GetState(); state {
+		case connectivity.Idle:
+			conn.Connect()
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("connection is %s", state)
+		default:
+			return nil
+		}
+	}
+}
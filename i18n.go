@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// messageID identifies a user-facing message in the catalog.
+type messageID string
+
+const (
+	msgReviewCreated messageID = "review.created"
+	msgReviewUpdated messageID = "review.updated"
+	msgReviewDeleted messageID = "review.deleted"
+
+	msgReviewPendingModeration messageID = "review.pending_moderation"
+	msgReviewsModerated        messageID = "review.moderated"
+
+	msgReviewUpdatedPendingModeration messageID = "review.updated_pending_moderation"
+
+	msgReviewReported messageID = "review.reported"
+	msgReviewVoted    messageID = "review.voted"
+	msgVoteRetracted  messageID = "review.vote_retracted"
+	msgReplySaved     messageID = "review.reply_saved"
+
+	msgCommentCreated                  messageID = "comment.created"
+	msgCommentPendingModeration        messageID = "comment.pending_moderation"
+	msgCommentUpdated                  messageID = "comment.updated"
+	msgCommentUpdatedPendingModeration messageID = "comment.updated_pending_moderation"
+	msgCommentDeleted                  messageID = "comment.deleted"
+	msgCommentsModerated               messageID = "comment.moderated"
+
+	msgQuestionAsked    messageID = "question.asked"
+	msgQuestionAnswered messageID = "question.answered"
+	msgAnswerUpvoted    messageID = "question.answer_upvoted"
+
+	msgTagSaved messageID = "tag.saved"
+
+	msgInvalidArgument       messageID = "error.invalid_argument"
+	msgNotFound              messageID = "error.not_found"
+	msgNotPurchased          messageID = "error.not_purchased"
+	msgUnauthenticated       messageID = "error.unauthenticated"
+	msgForbidden             messageID = "error.forbidden"
+	msgDependencyUnavailable messageID = "error.dependency_unavailable"
+	msgRateLimited           messageID = "error.rate_limited"
+	msgIdempotencyInProgress messageID = "error.idempotency_in_progress"
+	msgInternal              messageID = "error.internal"
+	msgContentRejected       messageID = "error.content_rejected"
+	msgAlreadyReported       messageID = "error.already_reported"
+	msgTagExists             messageID = "error.tag_exists"
+	msgSlowConsumer          messageID = "error.slow_consumer"
+	msgShuttingDown          messageID = "error.shutting_down"
+
+	msgProductIDInvalid        messageID = "violation.product_id"
+	msgNumStarRange            messageID = "violation.num_star"
+	msgImageDataURL            messageID = "violation.image_data_url"
+	msgImageBase64             messageID = "violation.image_base64"
+	msgIdempotencyKeyLength    messageID = "violation.idempotency_key_length"
+	msgIdempotencyKeyReused    messageID = "violation.idempotency_key_reused"
+	msgPageTokenInvalid        messageID = "violation.page_token"
+	msgModerationActionInvalid messageID = "violation.moderation_action"
+	msgModerationBatchSize     messageID = "violation.moderation_batch_size"
+	msgReviewIDInvalid         messageID = "violation.review_id"
+	msgContentEmpty            messageID = "violation.content_empty"
+	msgContentBannedWord       messageID = "violation.content_banned_word"
+	msgContentContactInfo      messageID = "violation.content_contact_info"
+	msgContentShouting         messageID = "violation.content_shouting"
+	msgContentRepetition       messageID = "violation.content_repetition"
+	msgContentTooShort         messageID = "violation.content_too_short"
+	msgReportReasonRequired    messageID = "violation.report_reason"
+	msgReportCommentLength     messageID = "violation.report_comment_length"
+	msgContentLength           messageID = "violation.content_length"
+	msgCommentIDInvalid        messageID = "violation.comment_id"
+	msgCommentParentInvalid    messageID = "violation.comment_parent"
+	msgQuestionIDInvalid       messageID = "violation.question_id"
+	msgDimensionInvalid        messageID = "violation.dimension"
+	msgDimensionDuplicated     messageID = "violation.dimension_duplicated"
+	msgTagInvalid              messageID = "violation.tag"
+	msgTagCount                messageID = "violation.tag_count"
+	msgTagNameLength           messageID = "violation.tag_name_length"
+	msgSearchQueryLength       messageID = "violation.search_query_length"
+	msgUserIDInvalid           messageID = "violation.user_id"
+	msgReviewIDsCount          messageID = "violation.review_ids_count"
+)
+
+const (
+	localeVi = "vi"
+	localeEn = "en"
+
+	defaultLocale = localeVi
+)
+
+// catalog holds the message bundles by locale. Messages may contain fmt verbs
+// which are filled from the arguments passed to localize.
+var catalog = map[string]map[messageID]string{
+	localeVi: {
+		msgReviewCreated: "Thêm review thành công",
+		msgReviewUpdated: "Cập nhật thành công",
+		msgReviewDeleted: "Xóa thành công",
+
+		msgReviewPendingModeration: "Thêm review thành công, review đang chờ duyệt",
+		msgReviewsModerated:        "Đã kiểm duyệt %d review",
+
+		msgReviewUpdatedPendingModeration: "Cập nhật thành công, review đang chờ duyệt",
+
+		msgReviewReported: "Cảm ơn bạn đã báo cáo, chúng tôi sẽ xem xét review này",
+		msgReviewVoted:    "Cảm ơn bạn đã đánh giá review này",
+		msgVoteRetracted:  "Đã hủy đánh giá",
+		msgReplySaved:     "Đã lưu phản hồi",
+
+		msgCommentCreated:                  "Đã gửi bình luận",
+		msgCommentPendingModeration:        "Đã gửi bình luận, bình luận đang chờ duyệt",
+		msgCommentUpdated:                  "Cập nhật bình luận thành công",
+		msgCommentUpdatedPendingModeration: "Cập nhật bình luận thành công, bình luận đang chờ duyệt",
+		msgCommentDeleted:                  "Đã xóa bình luận",
+		msgCommentsModerated:               "Đã kiểm duyệt %d bình luận",
+
+		msgQuestionAsked:    "Đã gửi câu hỏi",
+		msgQuestionAnswered: "Đã gửi câu trả lời",
+		msgAnswerUpvoted:    "Cảm ơn bạn đã bình chọn",
+
+		msgTagSaved: "Đã lưu thẻ",
+
+		msgInvalidArgument:       "Dữ liệu không hợp lệ",
+		msgNotFound:              "Không tìm thấy dữ liệu",
+		msgNotPurchased:          "Sản phẩm này chưa được mua",
+		msgUnauthenticated:       "Vui lòng đăng nhập để tiếp tục",
+		msgForbidden:             "Bạn không có quyền thực hiện thao tác này",
+		msgDependencyUnavailable: "Dịch vụ tạm thời không khả dụng, vui lòng thử lại sau",
+		msgRateLimited:           "Bạn thao tác quá nhanh, vui lòng thử lại sau",
+		msgIdempotencyInProgress: "Yêu cầu đang được xử lý, vui lòng thử lại sau",
+		msgInternal:              "Đã có lỗi xảy ra",
+		msgContentRejected:       "Nội dung không phù hợp",
+		msgAlreadyReported:       "Bạn đã báo cáo review này",
+		msgTagExists:             "Thẻ đã tồn tại",
+		msgSlowConsumer:          "Kết nối quá chậm, vui lòng kết nối lại",
+		msgShuttingDown:          "Máy chủ đang dừng, vui lòng kết nối lại",
+
+		msgProductIDInvalid:        "Mã sản phẩm không hợp lệ",
+		msgNumStarRange:            "Số sao phải từ %d đến %d",
+		msgImageDataURL:            "Ảnh phải là data url dạng base64",
+		msgImageBase64:             "Dữ liệu ảnh không phải base64 hợp lệ",
+		msgIdempotencyKeyLength:    "Idempotency key không được dài quá %d ký tự",
+		msgIdempotencyKeyReused:    "Idempotency key đã được dùng cho một yêu cầu khác",
+		msgPageTokenInvalid:        "Page token không hợp lệ",
+		msgModerationActionInvalid: "Hành động kiểm duyệt không hợp lệ",
+		msgModerationBatchSize:     "Cần từ 1 đến %d review",
+		msgReviewIDInvalid:         "Mã review không hợp lệ",
+		msgContentEmpty:            "Nội dung không được để trống",
+		msgContentBannedWord:       "Nội dung chứa từ ngữ không phù hợp",
+		msgContentContactInfo:      "Nội dung không được chứa đường dẫn, email hay số điện thoại",
+		msgContentShouting:         "Vui lòng không viết toàn chữ in hoa",
+		msgContentRepetition:       "Nội dung lặp lại quá nhiều",
+		msgContentTooShort:         "Vui lòng mô tả chi tiết hơn cho đánh giá này",
+		msgReportReasonRequired:    "Vui lòng chọn lý do báo cáo",
+		msgReportCommentLength:     "Nội dung báo cáo tối đa %d ký tự",
+		msgContentLength:           "Nội dung tối đa %d ký tự",
+		msgCommentIDInvalid:        "Mã bình luận không hợp lệ",
+		msgCommentParentInvalid:    "Chỉ có thể trả lời bình luận gốc của review này",
+		msgQuestionIDInvalid:       "Mã câu hỏi không hợp lệ",
+		msgDimensionInvalid:        "Tiêu chí đánh giá không áp dụng cho sản phẩm này",
+		msgDimensionDuplicated:     "Mỗi tiêu chí chỉ được đánh giá một lần",
+		msgTagInvalid:              "Thẻ không tồn tại hoặc đã ngừng sử dụng",
+		msgTagCount:                "Chọn tối đa %d thẻ",
+		msgTagNameLength:           "Tên thẻ từ 1 đến %d ký tự",
+		msgSearchQueryLength:       "Từ khóa tìm kiếm từ 1 đến %d ký tự",
+		msgUserIDInvalid:           "Mã người dùng không hợp lệ",
+		msgReviewIDsCount:          "Cần từ 1 đến %d mã review",
+	},
+	localeEn: {
+		msgReviewCreated: "Review added successfully",
+		msgReviewUpdated: "Updated successfully",
+		msgReviewDeleted: "Deleted successfully",
+
+		msgReviewPendingModeration: "Review added successfully and is awaiting moderation",
+		msgReviewsModerated:        "%d reviews moderated",
+
+		msgReviewUpdatedPendingModeration: "Updated successfully, the review is awaiting moderation",
+
+		msgReviewReported: "Thanks for your report, we will look into this review",
+		msgReviewVoted:    "Thanks for your feedback",
+		msgVoteRetracted:  "Your vote was removed",
+		msgReplySaved:     "Your reply was saved",
+
+		msgCommentCreated:                  "Your comment was posted",
+		msgCommentPendingModeration:        "Your comment was sent and is awaiting moderation",
+		msgCommentUpdated:                  "Comment updated successfully",
+		msgCommentUpdatedPendingModeration: "Comment updated successfully, it is awaiting moderation",
+		msgCommentDeleted:                  "Comment deleted",
+		msgCommentsModerated:               "%d comments moderated",
+
+		msgQuestionAsked:    "Your question was posted",
+		msgQuestionAnswered: "Your answer was posted",
+		msgAnswerUpvoted:    "Thanks for your vote",
+
+		msgTagSaved: "Tag saved",
+
+		msgInvalidArgument:       "Invalid request",
+		msgNotFound:              "Not found",
+		msgNotPurchased:          "You have not purchased this product",
+		msgUnauthenticated:       "Please sign in to continue",
+		msgForbidden:             "You are not allowed to perform this action",
+		msgDependencyUnavailable: "Service temporarily unavailable, please try again later",
+		msgRateLimited:           "Too many requests, please try again later",
+		msgIdempotencyInProgress: "The request is still being processed, please try again later",
+		msgInternal:              "Something went wrong",
+		msgContentRejected:       "The content is not allowed",
+		msgAlreadyReported:       "You have already reported this review",
+		msgTagExists:             "The tag already exists",
+		msgSlowConsumer:          "The connection is too slow, please reconnect",
+		msgShuttingDown:          "The server is shutting down, please reconnect",
+
+		msgProductIDInvalid:        "Product id is invalid",
+		msgNumStarRange:            "Rating must be between %d and %d stars",
+		msgImageDataURL:            "Image must be a base64 data url",
+		msgImageBase64:             "Image data is not valid base64",
+		msgIdempotencyKeyLength:    "Idempotency key must be at most %d characters",
+		msgIdempotencyKeyReused:    "Idempotency key was already used for a different request",
+		msgPageTokenInvalid:        "Page token is invalid",
+		msgModerationActionInvalid: "Moderation action is invalid",
+		msgModerationBatchSize:     "Between 1 and %d reviews are required",
+		msgReviewIDInvalid:         "Review id is invalid",
+		msgContentEmpty:            "Content must not be empty",
+		msgContentBannedWord:       "The content contains inappropriate language",
+		msgContentContactInfo:      "The content must not contain links, emails or phone numbers",
+		msgContentShouting:         "Please do not write in all caps",
+		msgContentRepetition:       "The content is too repetitive",
+		msgContentTooShort:         "Please describe your experience in more detail for this rating",
+		msgReportReasonRequired:    "A report reason is required",
+		msgReportCommentLength:     "The report comment must be at most %d characters",
+		msgContentLength:           "The content must be at most %d characters",
+		msgCommentIDInvalid:        "Comment id is invalid",
+		msgCommentParentInvalid:    "Only top level comments of this review can be replied to",
+		msgQuestionIDInvalid:       "Question id is invalid",
+		msgDimensionInvalid:        "This rating dimension does not apply to the product",
+		msgDimensionDuplicated:     "Each dimension can be rated only once",
+		msgTagInvalid:              "A tag does not exist or is no longer available",
+		msgTagCount:                "At most %d tags can be selected",
+		msgTagNameLength:           "The tag name must be 1 to %d characters",
+		msgSearchQueryLength:       "The search query must be 1 to %d characters",
+		msgUserIDInvalid:           "User id is invalid",
+		msgReviewIDsCount:          "Between 1 and %d review ids are required",
+	},
+}
+
+// localize returns the message in the locale requested by the caller,
+// falling back to the default locale.
+func localize(ctx context.Context, id messageID, args ...interface{}) string {
+	return translate(localeFromContext(ctx), id, args...)
+}
+
+func translate(locale string, id messageID, args ...interface{}) string {
+	msg, ok := catalog[locale][id]
+	if !ok {
+		msg, ok = catalog[defaultLocale][id]
+	}
+	if !ok {
+		return string(id)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// localeFromContext picks the best supported locale from the accept-language
+// metadata, e.g. "en-US,en;q=0.9,vi;q=0.8".
+func localeFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return defaultLocale
+	}
+	values := md.Get("accept-language")
+	if len(values) == 0 {
+		return defaultLocale
+	}
+	return matchLocale(strings.Join(values, ","))
+}
+
+func matchLocale(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := catalog[base]; ok && q > 0 {
+			candidates = append(candidates, candidate{base, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return defaultLocale
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
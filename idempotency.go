@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/repository"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// idempotencyStore records the responses of rpcs sent with an idempotency
+// key so that client retries replay them instead of running again.
+type idempotencyStore struct {
+	queries *repository.Queries
+	// ttl is how long a key and its response are kept
+	ttl time.Duration
+	// lockTimeout is how long a key stays claimed by a request that has not
+	// completed, e.g. because the replica crashed. It must exceed the deadline
+	// of the handlers it guards
+	lockTimeout time.Duration
+}
+
+// idempotencyKeyFrom returns the key of the request field, falling back to
+// the idempotency-key metadata.
+func idempotencyKeyFrom(ctx context.Context, fieldValue string) string {
+	if fieldValue != "" {
+		return fieldValue
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("idempotency-key"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requestFingerprint hashes the request without its idempotency key.
+func requestFingerprint(req proto.Message, clearKey func(proto.Message)) ([]byte, error) {
+	req = proto.Clone(req)
+	clearKey(req)
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// idempotentCall identifies one use of an idempotency key. Keys are scoped
+// by user and method.
+type idempotentCall struct {
+	userID      int64
+	method      string
+	key         string
+	fingerprint []byte
+}
+
+// runIdempotent runs handler once per key. A retry with the same payload
+// gets the stored response, while a different payload under the same key is
+// rejected. Failed calls release the key so that they can be retried.
+func runIdempotent[T proto.Message](ctx context.Context, store idempotencyStore, call idempotentCall, newResponse func() T, handler func() (T, error)) (T, error) {
+	var zero T
+	if len(call.key) > maxIdempotencyKeyLength {
+		return zero, errInvalidArgument(fieldViolation{field: "idempotency_key", description: msgIdempotencyKeyLength, args: []interface{}{maxIdempotencyKeyLength}})
+	}
+
+	now := time.Now()
+	claimed, err := store.queries.ClaimIdempotencyKey(ctx, repository.ClaimIdempotencyKeyParams{
+		UserID:      call.userID,
+		Method:      call.method,
+		Key:         call.key,
+		Fingerprint: call.fingerprint,
+		ExpiresAt:   now.Add(store.ttl),
+		StaleBefore: now.Add(-store.lockTimeout),
+	})
+	if err != nil {
+		return zero, err
+	}
+	if claimed == 0 {
+		return replay(ctx, store, call, newResponse)
+	}
+
+	resp, err := handler()
+
+	// the key is released or completed with a fresh context, the request
+	// context may be canceled by now. A key left claimed would run the
+	// handler again once its lock times out
+	storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err != nil {
+		if releaseErr := store.queries.ReleaseIdempotencyKey(storeCtx, repository.ReleaseIdempotencyKeyParams{
+			UserID: call.userID,
+			Method: call.method,
+			Key:    call.key,
+		}); releaseErr != nil {
+			log.Printf("can't release idempotency key %q: %v", call.key, releaseErr)
+		}
+		return zero, err
+	}
+
+	data, err := proto.Marshal(resp)
+	if err == nil {
+		err = store.queries.SaveIdempotentResponse(storeCtx, repository.SaveIdempotentResponseParams{
+			UserID:   call.userID,
+			Method:   call.method,
+			Key:      call.key,
+			Response: data,
+		})
+	}
+	if err != nil {
+		// the call itself succeeded, a retry will be rejected as in progress
+		// until the lock times out
+		log.Printf("can't save response of idempotency key %q: %v", call.key, err)
+	}
+	return resp, nil
+}
+
+func replay[T proto.Message](ctx context.Context, store idempotencyStore, call idempotentCall, newResponse func() T) (T, error) {
+	var zero T
+	stored, err := store.queries.GetIdempotencyKey(ctx, repository.GetIdempotencyKeyParams{
+		UserID: call.userID,
+		Method: call.method,
+		Key:    call.key,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// released by a failed call in the meantime
+		return zero, errIdempotencyConflict()
+	}
+	if err != nil {
+		return zero, err
+	}
+
+	if string(stored.Fingerprint) != string(call.fingerprint) {
+		return zero, errInvalidArgument(fieldViolation{field: "idempotency_key", description: msgIdempotencyKeyReused})
+	}
+	if stored.Response == nil {
+		return zero, errIdempotencyConflict()
+	}
+
+	resp := newResponse()
+	if err := proto.Unmarshal(stored.Response, resp); err != nil {
+		return zero, err
+	}
+	return resp, nil
+}
+
+// run deletes expired keys every interval until ctx is done.
+func (store idempotencyStore) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.queries.DeleteExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				log.Println("can't delete expired idempotency keys: ", err)
+			} else if deleted > 0 {
+				log.Printf("deleted %d expired idempotency keys", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+// fakeIdempotencyDB claims every key and records the responses saved. Like
+// a real database, it fails statements run with a done context.
+type fakeIdempotencyDB struct {
+	repository.DBTX
+	saved    []byte
+	released bool
+}
+
+func (db *fakeIdempotencyDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.Contains(query, "ClaimIdempotencyKey"):
+		return driverResult(1), nil
+	case strings.Contains(query, "SaveIdempotentResponse"):
+		db.saved = args[3].([]byte)
+	case strings.Contains(query, "ReleaseIdempotencyKey"):
+		db.released = true
+	}
+	return driverResult(0), nil
+}
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) {
+	return 0, errors.New("not supported")
+}
+
+func (r driverResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
+
+func TestRunIdempotentSavesResponseOfCanceledRequest(t *testing.T) {
+	db := &fakeIdempotencyDB{}
+	store := idempotencyStore{queries: repository.New(db), ttl: time.Hour, lockTimeout: time.Minute}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := runIdempotent(ctx, store, idempotentCall{userID: 1, method: "CreateReview", key: "k"}, func() *pb.CreateReviewResponse {
+		return &pb.CreateReviewResponse{}
+	}, func() (*pb.CreateReviewResponse, error) {
+		// the client gives up once the review is created
+		cancel()
+		return &pb.CreateReviewResponse{Message: "created"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.saved == nil {
+		t.Error("response not saved, retries would create the review again")
+	}
+}
+
+func TestRunIdempotentReleasesKeyOfCanceledRequest(t *testing.T) {
+	db := &fakeIdempotencyDB{}
+	store := idempotencyStore{queries: repository.New(db), ttl: time.Hour, lockTimeout: time.Minute}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := runIdempotent(ctx, store, idempotentCall{userID: 1, method: "CreateReview", key: "k"}, func() *pb.CreateReviewResponse {
+		return &pb.CreateReviewResponse{}
+	}, func() (*pb.CreateReviewResponse, error) {
+		cancel()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want the handler error", err)
+	}
+	if !db.released {
+		t.Error("key not released, retries would be rejected as in progress")
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jsonWebKey is a public key of a JWK set (RFC 7517).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// publicKey converts the JWK into a crypto public key.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// parseJWKS parses a JWK set, skipping keys that are not usable for
+// signature verification.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("jwks: skip key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable signing key")
+	}
+	return keys, nil
+}
+
+// keySource loads the raw JWK set.
+type keySource func(ctx context.Context) ([]byte, error)
+
+// fileKeySource reads the JWK set from a file, which is re-read on every
+// refresh so that a rotated file is picked up.
+func fileKeySource(path string) keySource {
+	return func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}
+}
+
+// urlKeySource fetches the JWK set over HTTP.
+func urlKeySource(url string, client *http.Client) keySource {
+	return func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	}
+}
+
+// keySet caches the verification keys. It is refreshed periodically and,
+// to follow key rotation, whenever a token is signed by an unknown key id,
+// at most once per minRefresh whether the refresh succeeds or not.
+type keySet struct {
+	source     keySource
+	minRefresh time.Duration
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+
+	// refreshMu serializes on-demand refreshes, so concurrent misses wait for
+	// a single fetch
+	refreshMu   sync.Mutex
+	lastAttempt time.Time
+}
+
+func newKeySet(ctx context.Context, source keySource, minRefresh time.Duration) (*keySet, error) {
+	ks := &keySet{source: source, minRefresh: minRefresh}
+	if err := ks.refresh(ctx); err != nil {
+		return nil, err
+	}
+	ks.lastAttempt = time.Now()
+	return ks, nil
+}
+
+func (ks *keySet) refresh(ctx context.Context) error {
+	data, err := ks.source(ctx)
+	if err != nil {
+		return fmt.Errorf("load jwks: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("parse jwks: %w", err)
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+	return nil
+}
+
+// run refreshes the keys every interval until ctx is done. Failed refreshes
+// keep the previous keys.
+func (ks *keySet) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ks.refresh(ctx); err != nil {
+				log.Println("jwks: refresh failed: ", err)
+			}
+		}
+	}
+}
+
+// key returns the key with the given id. An empty kid matches the only key
+// of a single-key set.
+func (ks *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+
+	ks.refreshMu.Lock()
+	defer ks.refreshMu.Unlock()
+	// the keys may have been refreshed while waiting for the lock
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	if time.Since(ks.lastAttempt) >= ks.minRefresh {
+		ks.lastAttempt = time.Now()
+		if err := ks.refresh(ctx); err != nil {
+			log.Println("jwks: refresh failed: ", err)
+		}
+		if key, ok := ks.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (ks *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
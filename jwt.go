@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha256" // register hash functions used by the algorithms below
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"google.golang.org/grpc/metadata"
+)
+
+var jwtHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// jwtClaims are the access token claims the review service relies on.
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	ID        json.RawMessage `json:"id"`
+	Role      json.RawMessage `json:"user_role"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+}
+
+// jwtVerifier validates access tokens locally against a JWK set.
+type jwtVerifier struct {
+	keys     *keySet
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// verify checks the signature, expiry, issuer and audience of the token.
+func (v jwtVerifier) verify(ctx context.Context, token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %w", err)
+	}
+	key, err := v.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed claims: %w", err)
+	}
+	if err := v.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (v jwtVerifier) validateClaims(claims *jwtClaims) error {
+	now := v.now()
+	if claims.ExpiresAt == nil {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(*claims.ExpiresAt, 0).Add(v.leeway)) {
+		return errors.New("token is expired")
+	}
+	if claims.NotBefore != nil && now.Add(v.leeway).Before(time.Unix(*claims.NotBefore, 0)) {
+		return errors.New("token is not valid yet")
+	}
+	if claims.Issuer != v.issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if !hasAudience(claims.Audience, v.audience) {
+		return errors.New("token is not intended for this audience")
+	}
+	return nil
+}
+
+// hasAudience matches the aud claim, which is either a string or an array.
+func hasAudience(raw json.RawMessage, audience string) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == audience
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		for _, aud := range list {
+			if aud == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// verifySignature checks a JWS signature. The algorithm must match the key
+// type, which rules out "none" and algorithm confusion attacks.
+func verifySignature(alg string, key crypto.PublicKey, signingInput, signature []byte) error {
+	if alg == "EdDSA" {
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(pub, signingInput, signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+
+	hash, ok := jwtHashes[alg]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signingInput)
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+		case "PS":
+			err = rsa.VerifyPSS(pub, hash, digest, signature, nil)
+		default:
+			err = fmt.Errorf("algorithm %q does not match rsa key", alg)
+		}
+		if err != nil {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || len(signature) != 2*size {
+			return errors.New("invalid signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("algorithm %q does not match key type", alg)
+	}
+}
+
+// identity extracts the user id and role from the claims. The id is read
+// from "id", falling back to "sub"; the role may be a number or a name and
+// is required, as customer is the zero role.
+func (c *jwtClaims) identity() (string, pb.UserRole, error) {
+	id := c.Subject
+	if len(c.ID) > 0 {
+		id = strings.Trim(string(c.ID), `"`)
+	}
+
+	raw := strings.Trim(string(c.Role), `"`)
+	if raw == "" || raw == "null" {
+		return "", 0, errors.New("token has no role")
+	}
+	if n, err := strconv.ParseInt(raw, 10, 32); err == nil {
+		return id, pb.UserRole(n), nil
+	}
+	if n, ok := pb.UserRole_value[strings.ToLower(raw)]; ok {
+		return id, pb.UserRole(n), nil
+	}
+	return "", 0, fmt.Errorf("unknown role %q", raw)
+}
+
+// localAuthenticator verifies the bearer token itself instead of calling
+// auth-service.
+type localAuthenticator struct {
+	verifier jwtVerifier
+}
+
+func (a localAuthenticator) authenticate(ctx context.Context, policy accessPolicy) (principal, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return principal{}, err
+	}
+	claims, err := a.verifier.verify(ctx, token)
+	if err != nil {
+		return principal{}, errUnauthenticated("INVALID_TOKEN", err)
+	}
+	id, role, err := claims.identity()
+	if err != nil {
+		return principal{}, errUnauthenticated("INVALID_CLAIMS", err)
+	}
+	return principalFromClaims(id, role, policy)
+}
+
+// bearerToken returns the token of the authorization metadata.
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", errUnauthenticated("MISSING_CREDENTIALS", nil)
+	}
+	token := strings.TrimSpace(values[0])
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	if token == "" {
+		return "", errUnauthenticated("MISSING_CREDENTIALS", nil)
+	}
+	return token, nil
+}
+
+// newAuthenticator returns the authenticator selected by AUTH_MODE: "remote"
+// (default) asks auth-service on every rpc, "local" verifies tokens against
+// the JWK set of JWKS_FILE or JWKS_URL, refreshed until ctx is done.
+func newAuthenticator(ctx context.Context, authClient pb.AuthServiceClient) (authenticator, error) {
+	mode := getEnv("AUTH_MODE", "remote")
+	switch mode {
+	case "remote":
+		return remoteAuthenticator{authClient: authClient}, nil
+	case "local":
+	default:
+		return nil, fmt.Errorf("unknown AUTH_MODE %q", mode)
+	}
+
+	// tokens of other issuers or for other services must not be accepted
+	issuer, audience := os.Getenv("JWT_ISSUER"), os.Getenv("JWT_AUDIENCE")
+	if issuer == "" || audience == "" {
+		return nil, errors.New("AUTH_MODE=local requires JWT_ISSUER and JWT_AUDIENCE")
+	}
+
+	var source keySource
+	switch {
+	case os.Getenv("JWKS_FILE") != "":
+		source = fileKeySource(os.Getenv("JWKS_FILE"))
+	case os.Getenv("JWKS_URL") != "":
+		source = urlKeySource(os.Getenv("JWKS_URL"), &http.Client{Timeout: 5 * time.Second})
+	default:
+		return nil, errors.New("AUTH_MODE=local requires JWKS_FILE or JWKS_URL")
+	}
+	keys, err := newKeySet(ctx, source, getEnvDuration("JWKS_MIN_REFRESH_INTERVAL", time.Minute))
+	if err != nil {
+		return nil, err
+	}
+	go keys.run(ctx, getEnvDuration("JWKS_REFRESH_INTERVAL", 15*time.Minute))
+
+	return localAuthenticator{
+		verifier: jwtVerifier{
+			keys:     keys,
+			issuer:   issuer,
+			audience: audience,
+			leeway:   getEnvDuration("JWT_LEEWAY", 30*time.Second),
+			now:      time.Now,
+		},
+	}, nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+)
+
+// testKeys are signing keys generated for the tests.
+type testKeys struct {
+	rsa      *rsa.PrivateKey
+	ec       *ecdsa.PrivateKey
+	ed       ed25519.PrivateKey
+	otherRSA *rsa.PrivateKey
+}
+
+func generateTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKeys{rsa: rsaKey, ec: ecKey, ed: edKey, otherRSA: otherRSA}
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// toJWK encodes a public key as a JWK.
+func toJWK(t *testing.T, kid string, key crypto.PublicKey) jsonWebKey {
+	t.Helper()
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		return jsonWebKey{Kty: "RSA", Kid: kid, N: b64(pub.N.Bytes()), E: b64(big.NewInt(int64(pub.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return jsonWebKey{Kty: "EC", Kid: kid, Crv: "P-256", X: b64(pub.X.FillBytes(make([]byte, size))), Y: b64(pub.Y.FillBytes(make([]byte, size)))}
+	case ed25519.PublicKey:
+		return jsonWebKey{Kty: "OKP", Kid: kid, Crv: "Ed25519", X: b64(pub)}
+	default:
+		t.Fatalf("unsupported key %T", key)
+		return jsonWebKey{}
+	}
+}
+
+func encodeJWKS(t *testing.T, keys ...jsonWebKey) []byte {
+	t.Helper()
+	data, err := json.Marshal(jsonWebKeySet{Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// signToken signs claims with alg, which may not match the key on purpose.
+func signToken(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64(header) + "." + b64(payload)
+
+	var signature []byte
+	var err error
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(input))
+	case *ecdsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		digest.Write([]byte(input))
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest.Sum(nil))
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case *rsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		digest.Write([]byte(input))
+		if alg == "PS256" {
+			signature, err = rsa.SignPSS(rand.Reader, k, crypto.SHA256, digest.Sum(nil), nil)
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest.Sum(nil))
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + b64(signature)
+}
+
+func staticSource(data []byte) keySource {
+	return func(context.Context) ([]byte, error) {
+		return data, nil
+	}
+}
+
+func TestJWTVerify(t *testing.T) {
+	keys := generateTestKeys(t)
+	jwks := encodeJWKS(t,
+		toJWK(t, "rsa", keys.rsa.Public()),
+		toJWK(t, "ec", keys.ec.Public()),
+		toJWK(t, "ed", keys.ed.Public()),
+	)
+	ks, err := newKeySet(context.Background(), staticSource(jwks), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	verifier := jwtVerifier{
+		keys:     ks,
+		issuer:   "auth-service",
+		audience: "review-service",
+		leeway:   30 * time.Second,
+		now:      func() time.Time { return now },
+	}
+
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"id":        7,
+			"user_role": "customer",
+			"iss":       "auth-service",
+			"aud":       "review-service",
+			"exp":       now.Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"rsa pkcs1", signToken(t, "RS256", "rsa", keys.rsa, claims(nil)), false},
+		{"rsa pss", signToken(t, "PS256", "rsa", keys.rsa, claims(nil)), false},
+		{"ecdsa", signToken(t, "ES256", "ec", keys.ec, claims(nil)), false},
+		{"ed25519", signToken(t, "EdDSA", "ed", keys.ed, claims(nil)), false},
+		{"audience list", signToken(t, "EdDSA", "ed", keys.ed, claims(map[string]interface{}{"aud": []string{"web", "review-service"}})), false},
+		{"expired within leeway", signToken(t, "EdDSA", "ed", keys.ed, claims(map[string]interface{}{"exp": now.Add(-10 * time.Second).Unix()})), false},
+		{"bad signature", signToken(t, "RS256", "rsa", keys.otherRSA, claims(nil)), true},
+		{"expired", signToken(t, "EdDSA", "ed", keys.ed, claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})), true},
+		{"no expiry", signToken(t, "EdDSA", "ed", keys.ed, claims(map[string]interface{}{"exp": nil})), true},
+		{"not valid yet", signToken(t, "EdDSA", "ed", keys.ed, claims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})), true},
+		{"wrong issuer", signToken(t, "EdDSA", "ed", keys.ed, claims(map[string]interface{}{"iss": "someone-else"})), true},
+		{"wrong audience", signToken(t, "EdDSA", "ed", keys.ed, claims(map[string]interface{}{"aud": "order-service"})), true},
+		{"ecdsa alg with rsa key", signToken(t, "ES256", "rsa", keys.rsa, claims(nil)), true},
+		{"rsa alg with ecdsa key", signToken(t, "RS256", "ec", keys.ec, claims(nil)), true},
+		{"eddsa alg with rsa key", signToken(t, "EdDSA", "rsa", keys.rsa, claims(nil)), true},
+		{"hmac alg", signToken(t, "HS256", "rsa", keys.rsa, claims(nil)), true},
+		{"none alg", signToken(t, "none", "ed", keys.ed, claims(nil)), true},
+		{"unknown kid", signToken(t, "EdDSA", "other", keys.ed, claims(nil)), true},
+		{"malformed", "not.a-token", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.verify(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTClaimsIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   string
+		wantID   string
+		wantRole pb.UserRole
+		wantErr  bool
+	}{
+		{"numeric id and role name", `{"id":7,"user_role":"admin"}`, "7", pb.UserRole_admin, false},
+		{"sub and role number", `{"sub":"8","user_role":1}`, "8", pb.UserRole_supplier, false},
+		{"customer role", `{"id":"9","user_role":"customer"}`, "9", pb.UserRole_customer, false},
+		{"missing role", `{"id":7}`, "", 0, true},
+		{"null role", `{"id":7,"user_role":null}`, "", 0, true},
+		{"unknown role", `{"id":7,"user_role":"root"}`, "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims jwtClaims
+			if err := json.Unmarshal([]byte(tt.claims), &claims); err != nil {
+				t.Fatal(err)
+			}
+			id, role, err := claims.identity()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("identity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.wantID || role != tt.wantRole {
+				t.Errorf("identity() = %q, %s, want %q, %s", id, role, tt.wantID, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestKeySetRefreshesOnUnknownKid(t *testing.T) {
+	keys := generateTestKeys(t)
+	oldSet := encodeJWKS(t, toJWK(t, "old", keys.rsa.Public()))
+	newSet := encodeJWKS(t, toJWK(t, "old", keys.rsa.Public()), toJWK(t, "new", keys.ed.Public()))
+
+	var calls int32
+	source := func(context.Context) ([]byte, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return oldSet, nil
+		}
+		return newSet, nil
+	}
+	ks, err := newKeySet(context.Background(), source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ks.key(context.Background(), "new"); err != nil {
+		t.Fatalf("rotated key not found: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("source called %d times, want 2", got)
+	}
+}
+
+func TestKeySetThrottlesFailedRefreshes(t *testing.T) {
+	keys := generateTestKeys(t)
+	jwks := encodeJWKS(t, toJWK(t, "rsa", keys.rsa.Public()))
+
+	var calls int32
+	source := func(context.Context) ([]byte, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return jwks, nil
+		}
+		return nil, errors.New("jwks endpoint is down")
+	}
+	ks, err := newKeySet(context.Background(), source, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// allow one on-demand refresh
+	ks.lastAttempt = time.Time{}
+
+	for i := 0; i < 3; i++ {
+		if _, err := ks.key(context.Background(), "random"); err == nil {
+			t.Fatal("unknown kid accepted")
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("source called %d times, want 2", got)
+	}
+}
+
+func TestKeySetCollapsesConcurrentRefreshes(t *testing.T) {
+	keys := generateTestKeys(t)
+	oldSet := encodeJWKS(t, toJWK(t, "old", keys.rsa.Public()))
+	newSet := encodeJWKS(t, toJWK(t, "new", keys.ed.Public()))
+
+	var calls int32
+	source := func(context.Context) ([]byte, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return oldSet, nil
+		}
+		time.Sleep(50 * time.Millisecond)
+		return newSet, nil
+	}
+	ks, err := newKeySet(context.Background(), source, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks.lastAttempt = time.Time{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ks.key(context.Background(), "new"); err != nil {
+				t.Errorf("rotated key not found: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("source called %d times, want 2", got)
+	}
+}
+
+func TestNewAuthenticatorMode(t *testing.T) {
+	keys := generateTestKeys(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, encodeJWKS(t, toJWK(t, "ed", keys.ed.Public())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		mode     string
+		jwksFile string
+		issuer   string
+		audience string
+		want     string
+		wantErr  bool
+	}{
+		{"default is remote", "", "", "", "", "remote", false},
+		{"remote", "remote", jwksFile, "", "", "remote", false},
+		{"local", "local", jwksFile, "auth-service", "review-service", "local", false},
+		{"local without jwks", "local", "", "auth-service", "review-service", "", true},
+		{"local without issuer", "local", jwksFile, "", "review-service", "", true},
+		{"local without audience", "local", jwksFile, "auth-service", "", "", true},
+		{"unknown mode", "offline", "", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AUTH_MODE", tt.mode)
+			t.Setenv("JWKS_FILE", tt.jwksFile)
+			t.Setenv("JWKS_URL", "")
+			t.Setenv("JWT_ISSUER", tt.issuer)
+			t.Setenv("JWT_AUDIENCE", tt.audience)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			auth, err := newAuthenticator(ctx, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newAuthenticator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := ""
+			switch auth.(type) {
+			case remoteAuthenticator:
+				got = "remote"
+			case localAuthenticator:
+				got = "local"
+			}
+			if got != tt.want {
+				t.Errorf("newAuthenticator() = %T, want %s", auth, tt.want)
+			}
+		})
+	}
+}
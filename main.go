@@ -1,25 +1,29 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/e-commerce-microservices/review-service/pb"
 	"github.com/e-commerce-microservices/review-service/repository"
+	"github.com/e-commerce-microservices/review-service/screening"
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	// postgres driver
 	_ "github.com/lib/pq"
 )
 
 func main() {
-	// create grpc server
-	grpcServer := grpc.NewServer()
-
 	// init user db connection
 	pgDSN := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
@@ -29,7 +33,6 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer conn.Close()
 	if err := conn.Ping(); err != nil {
 		log.Fatal("can't ping to user db", err)
 	}
@@ -37,8 +40,28 @@ func main() {
 	// init queries
 	queries := repository.New(conn)
 
+	// background workers run until shutdown
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+
+	// plaintext unless TLS is configured
+	clientCreds, err := clientCredentials(bgCtx)
+	if err != nil {
+		log.Fatal("can't load client tls credentials: ", err)
+	}
+
+	// each dependency gets its own timeout and circuit breaker
+	retry := clientPolicy{
+		maxAttempts: getEnvInt("CLIENT_MAX_ATTEMPTS", 3),
+		backoff:     getEnvDuration("CLIENT_RETRY_BACKOFF", 100*time.Millisecond),
+	}
+	breakerThreshold := getEnvInt("BREAKER_FAILURE_THRESHOLD", 5)
+	breakerOpenDuration := getEnvDuration("BREAKER_OPEN_DURATION", 30*time.Second)
+
 	// dial image client
-	imageServiceConn, err := grpc.Dial("image-service:8080", grpc.WithInsecure())
+	imagePolicy := retry
+	imagePolicy.timeout = getEnvDuration("IMAGE_SERVICE_TIMEOUT", 30*time.Second)
+	imageBreaker := newCircuitBreaker("image-service", breakerThreshold, breakerOpenDuration)
+	imageServiceConn, err := dialDependency("image-service:8080", clientCreds, imagePolicy, imageBreaker)
 	if err != nil {
 		log.Fatal("can't dial image service: ", err)
 	}
@@ -46,40 +69,200 @@ func main() {
 	imageClient := pb.NewImageServiceClient(imageServiceConn)
 
 	// dial auth client
-	authServiceConn, err := grpc.Dial("auth-service:8080", grpc.WithInsecure())
+	authPolicy := retry
+	authPolicy.timeout = getEnvDuration("AUTH_SERVICE_TIMEOUT", 3*time.Second)
+	authBreaker := newCircuitBreaker("auth-service", breakerThreshold, breakerOpenDuration)
+	authServiceConn, err := dialDependency("auth-service:8080", clientCreds, authPolicy, authBreaker)
 	if err != nil {
-		log.Fatal("can't dial image service: ", err)
+		log.Fatal("can't dial auth service: ", err)
 	}
 	authClient := pb.NewAuthServiceClient(authServiceConn)
 
 	// dial order client
-	orderServiceConn, err := grpc.Dial("order-service:8080", grpc.WithInsecure())
+	orderPolicy := retry
+	orderPolicy.timeout = getEnvDuration("ORDER_SERVICE_TIMEOUT", 5*time.Second)
+	orderBreaker := newCircuitBreaker("order-service", breakerThreshold, breakerOpenDuration)
+	orderServiceConn, err := dialDependency("order-service:8080", clientCreds, orderPolicy, orderBreaker)
 	if err != nil {
-		log.Fatal("can't dial image service: ", err)
+		log.Fatal("can't dial order service: ", err)
 	}
 	orderClient := pb.NewOrderServiceClient(orderServiceConn)
 
+	// keep responses of idempotent requests for client retries. A key must
+	// stay claimed while CreateReview may still be running, otherwise a retry
+	// takes it over and creates the review twice
+	createReviewTimeout := getEnvDuration("CREATE_REVIEW_TIMEOUT", 45*time.Second)
+	idempotency := idempotencyStore{
+		queries:     queries,
+		ttl:         getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		lockTimeout: getEnvDuration("IDEMPOTENCY_LOCK_TIMEOUT", createReviewTimeout+15*time.Second),
+	}
+	if idempotency.lockTimeout <= createReviewTimeout {
+		log.Fatalf("IDEMPOTENCY_LOCK_TIMEOUT %s must be longer than CREATE_REVIEW_TIMEOUT %s", idempotency.lockTimeout, createReviewTimeout)
+	}
+	go idempotency.run(bgCtx, getEnvDuration("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour))
+
+	// screen submitted content, with the built-in rules unless a file is given
+	screener := screening.Default()
+	if path := os.Getenv("SCREENING_RULES_FILE"); path != "" {
+		screener, err = screening.LoadFile(path)
+		if err != nil {
+			log.Fatal("can't load screening rules: ", err)
+		}
+	}
+
+	// relay review events written to the outbox
+	var outboxPublisher publisher = newWriterPublisher(os.Stdout)
+	var outboxFile *os.File
+	if path := os.Getenv("OUTBOX_FILE"); path != "" {
+		outboxFile, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatal("can't open outbox file: ", err)
+		}
+		outboxPublisher = newWriterPublisher(outboxFile)
+	}
+	relay := outboxRelay{
+		db:              conn,
+		queries:         queries,
+		publisher:       outboxPublisher,
+		batchSize:       int32(getEnvInt("OUTBOX_BATCH_SIZE", 100)),
+		retention:       getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+		cleanupInterval: getEnvDuration("OUTBOX_CLEANUP_INTERVAL", time.Hour),
+	}
+	go relay.run(bgCtx, getEnvDuration("OUTBOX_RELAY_INTERVAL", time.Second))
+
+	// sub-rating dimensions by product category
+	dimensions := defaultRatingDimensions
+	if path := os.Getenv("RATING_DIMENSIONS_FILE"); path != "" {
+		dimensions, err = loadRatingDimensions(path)
+		if err != nil {
+			log.Fatal("can't load rating dimensions: ", err)
+		}
+	}
+
+	// shutdown must outlast the slowest CreateReview
+	shutdownTimeout := getEnvDuration("SHUTDOWN_TIMEOUT", time.Minute)
+	if shutdownTimeout <= createReviewTimeout {
+		log.Printf("SHUTDOWN_TIMEOUT %s is not longer than CREATE_REVIEW_TIMEOUT %s, in-flight reviews may be cut off", shutdownTimeout, createReviewTimeout)
+	}
+
 	// create review service
 	service := reviewService{
-		queries:     queries,
-		authClient:  authClient,
-		orderClient: orderClient,
-		imageClient: imageClient,
+		db:                  conn,
+		queries:             queries,
+		authClient:          authClient,
+		orderClient:         orderClient,
+		imageClient:         imageClient,
+		idempotency:         idempotency,
+		screener:            screener,
+		autoApprove:         getEnv("SCREENING_AUTO_APPROVE", "false") == "true",
+		createReviewTimeout: createReviewTimeout,
+		reportThreshold:     getEnvInt("REPORT_HIDE_THRESHOLD", 3),
+		dimensions:          dimensions,
+		broker:              newReviewBroker(getEnvInt("WATCH_BUFFER_SIZE", 16)),
 	}
+
+	// deliver review changes notified by every replica to local watchers
+	watchListener := reviewListener{
+		dsn:        pgDSN,
+		srv:        service,
+		broker:     service.broker,
+		retryDelay: getEnvDuration("WATCH_LISTEN_RETRY_DELAY", 10*time.Second),
+	}
+	go watchListener.run(bgCtx)
+
+	// resolve callers from their token, locally or through auth service
+	auth, err := newAuthenticator(bgCtx, authClient)
+	if err != nil {
+		log.Fatal("can't create authenticator: ", err)
+	}
+
+	// limit request rates per user and per client ip
+	methodLimits, err := methodLimitsFromEnv()
+	if err != nil {
+		log.Fatal("invalid rate limits: ", err)
+	}
+	limiterBackend := newMemoryLimiter(getEnvDuration("RATE_LIMIT_TTL", 10*time.Minute))
+	go limiterBackend.run(bgCtx, time.Minute)
+	limiter := rateLimiter{
+		backend:        limiterBackend,
+		limits:         methodLimits,
+		trustedProxies: getEnvInt("RATE_LIMIT_TRUSTED_PROXIES", 0),
+	}
+
+	serverCreds, err := serverCredentials(bgCtx)
+	if err != nil {
+		log.Fatal("can't load server tls credentials: ", err)
+	}
+
+	// create grpc server, errors are translated after authentication so that
+	// auth failures get the same status mapping; client ips are rate limited
+	// before authentication and users after it
+	grpcServer := grpc.NewServer(
+		grpc.Creds(serverCreds),
+		grpc.ChainUnaryInterceptor(errorUnaryInterceptor, limiter.ipUnaryInterceptor, authUnaryInterceptor(auth), limiter.userUnaryInterceptor),
+		grpc.ChainStreamInterceptor(errorStreamInterceptor, authStreamInterceptor(auth)),
+	)
+
 	// register product service
 	pb.RegisterReviewServiceServer(grpcServer, service)
 
+	// register health service, backed by a background dependency checker
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	checker := newHealthChecker(
+		healthServer,
+		getEnvDuration("HEALTH_CHECK_INTERVAL", 10*time.Second),
+		getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		dependency{name: "postgres", critical: true, check: pingDB(conn)},
+		dependency{name: "auth-service", critical: true, check: connState(authServiceConn)},
+		dependency{name: "order-service", critical: true, check: connState(orderServiceConn)},
+		// images are uploaded best effort, reviews are still created without them
+		dependency{name: "image-service", critical: false, check: connState(imageServiceConn)},
+		// breaker states are reported for diagnostics only
+		dependency{name: "auth-service/breaker", check: authBreaker.check},
+		dependency{name: "order-service/breaker", check: orderBreaker.check},
+		dependency{name: "image-service/breaker", check: imageBreaker.check},
+	)
+	go checker.run(bgCtx)
+
 	// listen and serve
 	listener, err := net.Listen("tcp", ":8080")
 	if err != nil {
 		log.Fatal("cannot create listener: ", err)
 	}
 
-	log.Printf("start gRPC server on %s", listener.Addr().String())
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		log.Fatal("cannot create grpc server: ", err)
+	go func() {
+		log.Printf("start gRPC server on %s", listener.Addr().String())
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			log.Fatal("cannot create grpc server: ", err)
+		}
+	}()
+
+	// wait for termination signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	log.Printf("received %s, shutting down", sig)
+
+	// report NOT_SERVING so the service is taken out of rotation
+	stopBackground()
+	healthServer.Shutdown()
+
+	// drain in-flight rpcs before releasing dependencies they may still use
+	gracefulStop(grpcServer, shutdownTimeout)
+	closers := []namedCloser{
+		{"image service conn", imageServiceConn},
+		{"auth service conn", authServiceConn},
+		{"order service conn", orderServiceConn},
+		{"review db", conn},
+	}
+	if outboxFile != nil {
+		closers = append(closers, namedCloser{"outbox file", outboxFile})
 	}
+	closeAll(closers...)
+	log.Println("server stopped")
 }
 
 func init() {
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+	"github.com/e-commerce-microservices/review-service/screening"
+)
+
+const maxModerationBatch = 100
+
+// moderationTransition is the status an action moves a review to, and the
+// statuses it can be applied from.
+type moderationTransition struct {
+	to   repository.ReviewStatus
+	from []repository.ReviewStatus
+}
+
+var moderationTransitions = map[pb.ModerationAction]moderationTransition{
+	pb.ModerationAction_approve: {
+		to:   repository.ReviewStatusApproved,
+		from: []repository.ReviewStatus{repository.ReviewStatusPending, repository.ReviewStatusHidden, repository.ReviewStatusRejected},
+	},
+	pb.ModerationAction_reject: {
+		to:   repository.ReviewStatusRejected,
+		from: []repository.ReviewStatus{repository.ReviewStatusPending, repository.ReviewStatusHidden},
+	},
+	pb.ModerationAction_hide: {
+		to:   repository.ReviewStatusHidden,
+		from: []repository.ReviewStatus{repository.ReviewStatusPending, repository.ReviewStatusApproved},
+	},
+}
+
+func (t moderationTransition) allowedFrom(status repository.ReviewStatus) bool {
+	for _, from := range t.from {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
+
+// statusChange moves a review to another status on behalf of a moderator,
+// or of the system when moderatorID is not valid.
+type statusChange struct {
+	to          repository.ReviewStatus
+	reason      pb.ModerationReason
+	note        string
+	moderatorID sql.NullInt64
+}
+
+// changeReviewStatus updates the status of a review locked by the caller's
+// transaction, records the transition in the moderation history and
+// publishes the updated review.
+func changeReviewStatus(ctx context.Context, q *repository.Queries, review repository.Review, change statusChange) error {
+	err := q.UpdateReviewStatus(ctx, repository.UpdateReviewStatusParams{
+		ID:               review.ID,
+		Status:           change.to,
+		ModerationReason: change.reason.String(),
+		ModeratorID:      change.moderatorID,
+	})
+	if err != nil {
+		return err
+	}
+	err = q.InsertModerationEvent(ctx, repository.InsertModerationEventParams{
+		ReviewID:    review.ID,
+		FromStatus:  review.Status,
+		ToStatus:    change.to,
+		Reason:      change.reason.String(),
+		Note:        change.note,
+		ModeratorID: change.moderatorID,
+	})
+	if err != nil {
+		return err
+	}
+	previous := review.Status
+	review.Status = change.to
+	return enqueueReviewEvent(ctx, q, eventReviewUpdated, previous, review)
+}
+
+// screeningReasons maps the reasons of flagged content to moderation reasons.
+var screeningReasons = map[string]pb.ModerationReason{
+	screening.ReasonBannedWord: pb.ModerationReason_offensive,
+	screening.ReasonURL:        pb.ModerationReason_spam,
+	screening.ReasonEmail:      pb.ModerationReason_personal_info,
+	screening.ReasonPhone:      pb.ModerationReason_personal_info,
+	screening.ReasonShouting:   pb.ModerationReason_spam,
+	screening.ReasonRepetition: pb.ModerationReason_spam,
+	screening.ReasonTooShort:   pb.ModerationReason_other,
+}
+
+// screenContent runs new or edited content through the screening pipeline
+// and returns the status it should be stored with. Flagged content waits for
+// moderation, as does clean content unless auto approval is enabled.
+func (srv reviewService) screenContent(field, content string, numStar int32) (repository.ReviewStatus, pb.ModerationReason, error) {
+	verdict := srv.screener.Screen(screening.Submission{Content: content, NumStar: numStar})
+	switch verdict.Action {
+	case screening.Reject:
+		return "", pb.ModerationReason_no_reason, errContentRejected(field, verdict.Reason)
+	case screening.Flag:
+		return repository.ReviewStatusPending, screeningReasons[verdict.Reason], nil
+	}
+	if srv.autoApprove {
+		return repository.ReviewStatusApproved, pb.ModerationReason_no_reason, nil
+	}
+	return repository.ReviewStatusPending, pb.ModerationReason_no_reason, nil
+}
+
+// editedStatus returns the status of edited content from the status it had
+// and the screening result. Edits never undo a moderation decision: rejected
+// or hidden content waits for moderation again instead of being approved.
+func editedStatus(current, screened repository.ReviewStatus) repository.ReviewStatus {
+	if screened == repository.ReviewStatusApproved && current != repository.ReviewStatusApproved && current != repository.ReviewStatusPending {
+		return repository.ReviewStatusPending
+	}
+	return screened
+}
+
+// refuseRejectedContent screens content which is published without
+// moderation, only refusing what the pipeline rejects.
+func (srv reviewService) refuseRejectedContent(field, content string) error {
+	verdict := srv.screener.Screen(screening.Submission{Content: content})
+	if verdict.Action == screening.Reject {
+		return errContentRejected(field, verdict.Reason)
+	}
+	return nil
+}
+
+func (srv reviewService) ListPendingReviews(ctx context.Context, req *pb.ListPendingReviewsRequest) (*pb.ListPendingReviewsResponse, error) {
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	// oldest first, so the queue is worked in submission order; reviews
+	// hidden by reports stay hidden until a moderator decides on them
+	reviews, err := srv.queries.ListReviewsAwaitingModeration(ctx, repository.ListReviewsAwaitingModerationParams{
+		Limit:   pageSize,
+		AfterID: afterID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := srv.toPbReviews(ctx, reviews)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListPendingReviewsResponse{ListReview: result}
+	if len(reviews) > 0 {
+		resp.NextPageToken = nextPageToken(len(reviews), pageSize, reviews[len(reviews)-1].ID)
+	}
+	return resp, nil
+}
+
+func (srv reviewService) ModerateReviews(ctx context.Context, req *pb.ModerateReviewsRequest) (*pb.ModerateReviewsResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, ok := moderationTransitions[req.GetAction()]
+	var violations []fieldViolation
+	if !ok {
+		violations = append(violations, fieldViolation{field: "action", description: msgModerationActionInvalid})
+	}
+	if n := len(req.GetReviewIds()); n == 0 || n > maxModerationBatch {
+		violations = append(violations, fieldViolation{field: "review_ids", description: msgModerationBatchSize, args: []interface{}{maxModerationBatch}})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	resp := &pb.ModerateReviewsResponse{}
+	seen := make(map[int64]bool, len(req.GetReviewIds()))
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		for _, id := range req.GetReviewIds() {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			review, err := q.GetReviewForUpdate(ctx, id)
+			if errors.Is(err, sql.ErrNoRows) {
+				resp.SkippedReviewIds = append(resp.SkippedReviewIds, id)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !transition.allowedFrom(review.Status) {
+				resp.SkippedReviewIds = append(resp.SkippedReviewIds, id)
+				continue
+			}
+
+			err = changeReviewStatus(ctx, q, review, statusChange{
+				to:          transition.to,
+				reason:      req.GetReason(),
+				note:        req.GetNote(),
+				moderatorID: sql.NullInt64{Int64: caller.userID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			resp.ModeratedReviewIds = append(resp.ModeratedReviewIds, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Message = localize(ctx, msgReviewsModerated, len(resp.ModeratedReviewIds))
+	return resp, nil
+}
+
+func (srv reviewService) GetModerationHistory(ctx context.Context, req *pb.GetModerationHistoryRequest) (*pb.GetModerationHistoryResponse, error) {
+	if _, err := srv.queries.GetReviewByID(ctx, req.GetReviewId()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errNotFound("review", req.GetReviewId())
+		}
+		return nil, err
+	}
+
+	events, err := srv.queries.ListModerationEvents(ctx, req.GetReviewId())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.ModerationEvent, 0, len(events))
+	for _, event := range events {
+		result = append(result, &pb.ModerationEvent{
+			ReviewId:    event.ReviewID,
+			FromStatus:  toPbStatus(event.FromStatus),
+			ToStatus:    toPbStatus(event.ToStatus),
+			Reason:      pb.ModerationReason(pb.ModerationReason_value[event.Reason]),
+			Note:        event.Note,
+			ModeratorId: event.ModeratorID.Int64,
+			CreatedAt:   event.CreatedAt.Unix(),
+		})
+	}
+	return &pb.GetModerationHistoryResponse{ListEvent: result}, nil
+}
+
+// toPbStatus converts a stored status, whose names match the proto enum.
+func toPbStatus(status repository.ReviewStatus) pb.ReviewStatus {
+	return pb.ReviewStatus(pb.ReviewStatus_value[string(status)])
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+func TestEditedStatus(t *testing.T) {
+	tests := []struct {
+		current  repository.ReviewStatus
+		screened repository.ReviewStatus
+		want     repository.ReviewStatus
+	}{
+		{repository.ReviewStatusApproved, repository.ReviewStatusApproved, repository.ReviewStatusApproved},
+		{repository.ReviewStatusPending, repository.ReviewStatusApproved, repository.ReviewStatusApproved},
+		{repository.ReviewStatusRejected, repository.ReviewStatusApproved, repository.ReviewStatusPending},
+		{repository.ReviewStatusHidden, repository.ReviewStatusApproved, repository.ReviewStatusPending},
+		{repository.ReviewStatusApproved, repository.ReviewStatusPending, repository.ReviewStatusPending},
+		{repository.ReviewStatusHidden, repository.ReviewStatusPending, repository.ReviewStatusPending},
+	}
+	for _, tt := range tests {
+		if got := editedStatus(tt.current, tt.screened); got != tt.want {
+			t.Errorf("editedStatus(%s, %s) = %s, want %s", tt.current, tt.screened, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+// Review domain event types.
+const (
+	eventReviewCreated = "ReviewCreated"
+	eventReviewUpdated = "ReviewUpdated"
+	eventReviewDeleted = "ReviewDeleted"
+)
+
+// reviewEventPayload is the payload of review events, the state of the
+// review after the change.
+type reviewEventPayload struct {
+	ReviewID  int64  `json:"review_id"`
+	UserID    int64  `json:"user_id"`
+	ProductID int64  `json:"product_id"`
+	NumStar   int32  `json:"num_star"`
+	Content   string `json:"content"`
+	Status    string `json:"status"`
+}
+
+// enqueueReviewEvent writes a review event to the outbox. It must be called
+// with the queries of the transaction changing the review, so the event is
+// stored, and watchers notified, if and only if the change is. previous is
+// the status of the review before the change, empty for new reviews.
+func enqueueReviewEvent(ctx context.Context, q *repository.Queries, eventType string, previous repository.ReviewStatus, review repository.Review) error {
+	payload, err := json.Marshal(reviewEventPayload{
+		ReviewID:  review.ID,
+		UserID:    review.UserID,
+		ProductID: review.ProductID,
+		NumStar:   review.NumStar,
+		Content:   review.Content,
+		Status:    string(review.Status),
+	})
+	if err != nil {
+		return err
+	}
+	err = q.InsertOutboxEvent(ctx, repository.InsertOutboxEventParams{
+		AggregateID: review.ID,
+		EventType:   eventType,
+		Payload:     payload,
+	})
+	if err != nil {
+		return err
+	}
+	return notifyReviewEvent(ctx, q, eventType, previous, review)
+}
+
+// outboxMessage is an event as delivered to publishers.
+type outboxMessage struct {
+	// ID identifies the event across redeliveries, consumers drop events
+	// whose ID they already processed. IDs are assigned when events are
+	// written, not committed, and relays of other replicas run concurrently,
+	// so events may arrive out of ID order: remember the IDs seen rather
+	// than the highest one.
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID int64           `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// publisher delivers outbox events to consumers.
+type publisher interface {
+	publish(ctx context.Context, msg outboxMessage) error
+}
+
+// writerPublisher writes events as JSON lines, e.g. to stdout or a file
+// tailed by a log shipper.
+type writerPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newWriterPublisher(w io.Writer) *writerPublisher {
+	return &writerPublisher{w: w}
+}
+
+func (p *writerPublisher) publish(_ context.Context, msg outboxMessage) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
+
+// outboxRelay delivers outbox events at least once: events are marked
+// published only after the publisher accepted them, so a crash in between
+// delivers them again.
+type outboxRelay struct {
+	db        *sql.DB
+	queries   *repository.Queries
+	publisher publisher
+	batchSize int32
+	// retention is how long published events are kept, they are deleted
+	// every cleanupInterval
+	retention       time.Duration
+	cleanupInterval time.Duration
+}
+
+func (relay outboxRelay) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(relay.cleanupInterval)
+	defer cleanup.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			relay.drain(ctx)
+		case <-cleanup.C:
+			relay.cleanup(ctx)
+		}
+	}
+}
+
+// cleanup deletes the events published before the retention period.
+func (relay outboxRelay) cleanup(ctx context.Context) {
+	deleted, err := relay.queries.DeletePublishedOutboxEvents(ctx, time.Now().Add(-relay.retention))
+	if err != nil {
+		log.Println("can't delete published outbox events: ", err)
+	} else if deleted > 0 {
+		log.Printf("deleted %d published outbox events", deleted)
+	}
+}
+
+// drain relays batches until the outbox is empty or delivery fails.
+func (relay outboxRelay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := relay.relayBatch(ctx)
+		if err != nil {
+			log.Println("can't relay outbox events: ", err)
+			return
+		}
+		if n < int(relay.batchSize) {
+			return
+		}
+	}
+}
+
+// relayBatch publishes the oldest unpublished events in ID order. The
+// events stay locked until they are marked, so concurrent relays don't
+// deliver them twice, but they skip locked events and may deliver later
+// ones first. When publishing fails the events delivered before are still marked.
+func (relay outboxRelay) relayBatch(ctx context.Context) (int, error) {
+	tx, err := relay.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		// no-op once committed
+		_ = tx.Rollback()
+	}()
+	q := relay.queries.WithTx(tx)
+
+	events, err := q.ClaimOutboxEvents(ctx, relay.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	published := make([]int64, 0, len(events))
+	var publishErr error
+	for _, event := range events {
+		publishErr = relay.publisher.publish(ctx, outboxMessage{
+			ID:          event.ID,
+			Type:        event.EventType,
+			AggregateID: event.AggregateID,
+			OccurredAt:  event.CreatedAt,
+			Payload:     event.Payload,
+		})
+		if publishErr != nil {
+			publishErr = fmt.Errorf("publish event %d: %w", event.ID, publishErr)
+			break
+		}
+		published = append(published, event.ID)
+	}
+
+	if len(published) > 0 {
+		if err := q.MarkOutboxEventsPublished(ctx, published); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(published), publishErr
+}
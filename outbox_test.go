@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+// memoryPublisher keeps published events in memory. It fails the events
+// listed in failIDs.
+type memoryPublisher struct {
+	mu       sync.Mutex
+	messages []outboxMessage
+	failIDs  map[int64]bool
+}
+
+func (p *memoryPublisher) publish(_ context.Context, msg outboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failIDs[msg.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// publishedIDs returns the ids of the events published so far, in order.
+func (p *memoryPublisher) publishedIDs() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ids := make([]int64, 0, len(p.messages))
+	for _, msg := range p.messages {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
+
+// fakeOutboxDB serves the outbox queries of the relay from memory. Events
+// marked published in a transaction only count once it commits.
+type fakeOutboxDB struct {
+	mu         sync.Mutex
+	events     []repository.OutboxEvent
+	failCommit bool
+}
+
+func newFakeOutboxDB(n int) *fakeOutboxDB {
+	db := &fakeOutboxDB{}
+	for i := 1; i <= n; i++ {
+		db.events = append(db.events, repository.OutboxEvent{
+			ID:          int64(i),
+			AggregateID: int64(100 + i),
+			EventType:   eventReviewCreated,
+			Payload:     []byte(`{}`),
+			CreatedAt:   time.Unix(int64(i), 0),
+		})
+	}
+	return db
+}
+
+func (db *fakeOutboxDB) Connect(context.Context) (driver.Conn, error) {
+	return &fakeOutboxConn{db: db}, nil
+}
+
+func (db *fakeOutboxDB) Driver() driver.Driver {
+	return nil
+}
+
+func (db *fakeOutboxDB) relay(publisher publisher, batchSize int32) outboxRelay {
+	conn := sql.OpenDB(db)
+	return outboxRelay{db: conn, queries: repository.New(conn), publisher: publisher, batchSize: batchSize}
+}
+
+func (db *fakeOutboxDB) unpublished() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	n := 0
+	for _, event := range db.events {
+		if !event.PublishedAt.Valid {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeOutboxConn struct {
+	db     *fakeOutboxDB
+	marked []int64
+}
+
+func (c *fakeOutboxConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeOutboxConn) Close() error {
+	return nil
+}
+
+func (c *fakeOutboxConn) Begin() (driver.Tx, error) {
+	c.marked = nil
+	return c, nil
+}
+
+func (c *fakeOutboxConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if c.db.failCommit {
+		return errors.New("connection lost")
+	}
+	for _, id := range c.marked {
+		c.db.events[id-1].PublishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	return nil
+}
+
+func (c *fakeOutboxConn) Rollback() error {
+	c.marked = nil
+	return nil
+}
+
+func (c *fakeOutboxConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "ClaimOutboxEvents") {
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	rows := &fakeRows{columns: outboxColumns}
+	for _, event := range c.db.events {
+		if len(rows.values) == int(args[0].Value.(int64)) {
+			break
+		}
+		if !event.PublishedAt.Valid {
+			rows.values = append(rows.values, []driver.Value{event.ID, event.AggregateID, event.EventType, []byte(event.Payload), event.CreatedAt, nil})
+		}
+	}
+	return rows, nil
+}
+
+func (c *fakeOutboxConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.Contains(query, "MarkOutboxEventsPublished") {
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	// pq.Array encodes the ids as "{1,2,3}"
+	for _, s := range strings.Split(strings.Trim(args[0].Value.(string), "{}"), ",") {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		c.marked = append(c.marked, id)
+	}
+	return driver.RowsAffected(len(c.marked)), nil
+}
+
+var outboxColumns = []string{"id", "aggregate_id", "event_type", "payload", "created_at", "published_at"}
+
+// fakeRows serves query results from memory.
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOutboxRelayDeliversInOrder(t *testing.T) {
+	db := newFakeOutboxDB(7)
+	publisher := &memoryPublisher{}
+	db.relay(publisher, 3).drain(context.Background())
+
+	if got, want := publisher.publishedIDs(), []int64{1, 2, 3, 4, 5, 6, 7}; !equalIDs(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+	if n := db.unpublished(); n != 0 {
+		t.Errorf("%d events left unpublished", n)
+	}
+}
+
+func TestOutboxRelayMarksDeliveredEventsOfFailedBatch(t *testing.T) {
+	db := newFakeOutboxDB(5)
+	publisher := &memoryPublisher{failIDs: map[int64]bool{3: true}}
+	relay := db.relay(publisher, 5)
+
+	n, err := relay.relayBatch(context.Background())
+	if err == nil {
+		t.Fatal("publish failure not reported")
+	}
+	if n != 2 || db.unpublished() != 3 {
+		t.Fatalf("marked %d events, %d left, want 2 marked and 3 left", n, db.unpublished())
+	}
+
+	// the failed event is delivered first once the publisher recovers
+	publisher.failIDs = nil
+	relay.drain(context.Background())
+	if got, want := publisher.publishedIDs(), []int64{1, 2, 3, 4, 5}; !equalIDs(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestOutboxRelayRedeliversUnmarkedEvents(t *testing.T) {
+	db := newFakeOutboxDB(2)
+	publisher := &memoryPublisher{}
+	relay := db.relay(publisher, 10)
+
+	// events were published but marking them never committed, as if the
+	// replica crashed in between
+	db.failCommit = true
+	if _, err := relay.relayBatch(context.Background()); err == nil {
+		t.Fatal("commit failure not reported")
+	}
+	db.failCommit = false
+	relay.drain(context.Background())
+
+	if got, want := publisher.publishedIDs(), []int64{1, 2, 1, 2}; !equalIDs(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+	if n := db.unpublished(); n != 0 {
+		t.Errorf("%d events left unpublished", n)
+	}
+}
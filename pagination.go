@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// normalizePageSize applies the default and maximum page sizes.
+func normalizePageSize(requested int32) int32 {
+	switch {
+	case requested <= 0:
+		return defaultPageSize
+	case requested > maxPageSize:
+		return maxPageSize
+	default:
+		return requested
+	}
+}
+
+// encodePageToken returns an opaque token resuming a listing after lastID.
+func encodePageToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+// decodePageToken returns the id a listing resumes after, 0 for the first page.
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidPageToken()
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 0 {
+		return 0, errInvalidPageToken()
+	}
+	return id, nil
+}
+
+// encodeRankedPageToken returns a token resuming a listing ordered by rank,
+// then id, after the given entry.
+func encodeRankedPageToken(rank float32, lastID int64) string {
+	raw := strconv.FormatFloat(float64(rank), 'g', -1, 32) + ":" + strconv.FormatInt(lastID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeRankedPageToken returns the rank and id a ranked listing resumes
+// after, with a 0 id for the first page.
+func decodeRankedPageToken(token string) (float32, int64, error) {
+	if token == "" {
+		return 0, 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, 0, errInvalidPageToken()
+	}
+	rankPart, idPart, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, 0, errInvalidPageToken()
+	}
+	rank, err := strconv.ParseFloat(rankPart, 32)
+	if err != nil {
+		return 0, 0, errInvalidPageToken()
+	}
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, 0, errInvalidPageToken()
+	}
+	return float32(rank), id, nil
+}
+
+func errInvalidPageToken() error {
+	return errInvalidArgument(fieldViolation{field: "page_token", description: msgPageTokenInvalid})
+}
+
+// nextPageToken returns the token of the next page, or "" when the page was
+// not full and there is nothing left.
+func nextPageToken(count int, pageSize int32, lastID int64) string {
+	if count < int(pageSize) {
+		return ""
+	}
+	return encodePageToken(lastID)
+}
@@ -28,6 +28,40 @@ type ReviewServiceClient interface {
 	UpdateReview(ctx context.Context, in *UpdateReviewRequest, opts ...grpc.CallOption) (*UpdateReviewResponse, error)
 	DeleteReview(ctx context.Context, in *DeleteReviewRequest, opts ...grpc.CallOption) (*DeleteReviewResponse, error)
 	GetAllReviewByProductID(ctx context.Context, in *GetAllReviewByProductIDRequest, opts ...grpc.CallOption) (*GetAllReviewByProductIDResponse, error)
+	ListPendingReviews(ctx context.Context, in *ListPendingReviewsRequest, opts ...grpc.CallOption) (*ListPendingReviewsResponse, error)
+	ModerateReviews(ctx context.Context, in *ModerateReviewsRequest, opts ...grpc.CallOption) (*ModerateReviewsResponse, error)
+	GetModerationHistory(ctx context.Context, in *GetModerationHistoryRequest, opts ...grpc.CallOption) (*GetModerationHistoryResponse, error)
+	ReportReview(ctx context.Context, in *ReportReviewRequest, opts ...grpc.CallOption) (*ReportReviewResponse, error)
+	ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error)
+	VoteReview(ctx context.Context, in *VoteReviewRequest, opts ...grpc.CallOption) (*VoteReviewResponse, error)
+	RetractVote(ctx context.Context, in *RetractVoteRequest, opts ...grpc.CallOption) (*RetractVoteResponse, error)
+	ReplyToReview(ctx context.Context, in *ReplyToReviewRequest, opts ...grpc.CallOption) (*ReplyToReviewResponse, error)
+	CreateComment(ctx context.Context, in *CreateCommentRequest, opts ...grpc.CallOption) (*CreateCommentResponse, error)
+	UpdateComment(ctx context.Context, in *UpdateCommentRequest, opts ...grpc.CallOption) (*UpdateCommentResponse, error)
+	DeleteComment(ctx context.Context, in *DeleteCommentRequest, opts ...grpc.CallOption) (*DeleteCommentResponse, error)
+	ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*ListCommentsResponse, error)
+	ListPendingComments(ctx context.Context, in *ListPendingCommentsRequest, opts ...grpc.CallOption) (*ListPendingCommentsResponse, error)
+	ModerateComments(ctx context.Context, in *ModerateCommentsRequest, opts ...grpc.CallOption) (*ModerateCommentsResponse, error)
+	AskQuestion(ctx context.Context, in *AskQuestionRequest, opts ...grpc.CallOption) (*AskQuestionResponse, error)
+	AnswerQuestion(ctx context.Context, in *AnswerQuestionRequest, opts ...grpc.CallOption) (*AnswerQuestionResponse, error)
+	ListQuestions(ctx context.Context, in *ListQuestionsRequest, opts ...grpc.CallOption) (*ListQuestionsResponse, error)
+	UpvoteAnswer(ctx context.Context, in *UpvoteAnswerRequest, opts ...grpc.CallOption) (*UpvoteAnswerResponse, error)
+	GetProductRatingSummary(ctx context.Context, in *GetProductRatingSummaryRequest, opts ...grpc.CallOption) (*GetProductRatingSummaryResponse, error)
+	CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*CreateTagResponse, error)
+	UpdateTag(ctx context.Context, in *UpdateTagRequest, opts ...grpc.CallOption) (*UpdateTagResponse, error)
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
+	GetProductTagFrequencies(ctx context.Context, in *GetProductTagFrequenciesRequest, opts ...grpc.CallOption) (*GetProductTagFrequenciesResponse, error)
+	SearchReviews(ctx context.Context, in *SearchReviewsRequest, opts ...grpc.CallOption) (*SearchReviewsResponse, error)
+	GetMyReviews(ctx context.Context, in *GetMyReviewsRequest, opts ...grpc.CallOption) (*GetMyReviewsResponse, error)
+	// admins only; reviews come without product details, no dependency
+	// resolves a product by id
+	GetReviewsByUser(ctx context.Context, in *GetReviewsByUserRequest, opts ...grpc.CallOption) (*GetReviewsByUserResponse, error)
+	// returns published reviews, and unpublished ones to their author and
+	// admins when called with a token
+	GetReview(ctx context.Context, in *GetReviewRequest, opts ...grpc.CallOption) (*GetReviewResponse, error)
+	GetReviews(ctx context.Context, in *GetReviewsRequest, opts ...grpc.CallOption) (*GetReviewsResponse, error)
+	ListReviewableOrders(ctx context.Context, in *ListReviewableOrdersRequest, opts ...grpc.CallOption) (*ListReviewableOrdersResponse, error)
+	WatchProductReviews(ctx context.Context, in *WatchProductReviewsRequest, opts ...grpc.CallOption) (ReviewService_WatchProductReviewsClient, error)
 }
 
 type reviewServiceClient struct {
@@ -83,6 +117,299 @@ func (c *reviewServiceClient) GetAllReviewByProductID(ctx context.Context, in *G
 	return out, nil
 }
 
+func (c *reviewServiceClient) ListPendingReviews(ctx context.Context, in *ListPendingReviewsRequest, opts ...grpc.CallOption) (*ListPendingReviewsResponse, error) {
+	out := new(ListPendingReviewsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ListPendingReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ModerateReviews(ctx context.Context, in *ModerateReviewsRequest, opts ...grpc.CallOption) (*ModerateReviewsResponse, error) {
+	out := new(ModerateReviewsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ModerateReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) GetModerationHistory(ctx context.Context, in *GetModerationHistoryRequest, opts ...grpc.CallOption) (*GetModerationHistoryResponse, error) {
+	out := new(GetModerationHistoryResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/GetModerationHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ReportReview(ctx context.Context, in *ReportReviewRequest, opts ...grpc.CallOption) (*ReportReviewResponse, error) {
+	out := new(ReportReviewResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ReportReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error) {
+	out := new(ListReportsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ListReports", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) VoteReview(ctx context.Context, in *VoteReviewRequest, opts ...grpc.CallOption) (*VoteReviewResponse, error) {
+	out := new(VoteReviewResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/VoteReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) RetractVote(ctx context.Context, in *RetractVoteRequest, opts ...grpc.CallOption) (*RetractVoteResponse, error) {
+	out := new(RetractVoteResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/RetractVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ReplyToReview(ctx context.Context, in *ReplyToReviewRequest, opts ...grpc.CallOption) (*ReplyToReviewResponse, error) {
+	out := new(ReplyToReviewResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ReplyToReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) CreateComment(ctx context.Context, in *CreateCommentRequest, opts ...grpc.CallOption) (*CreateCommentResponse, error) {
+	out := new(CreateCommentResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/CreateComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) UpdateComment(ctx context.Context, in *UpdateCommentRequest, opts ...grpc.CallOption) (*UpdateCommentResponse, error) {
+	out := new(UpdateCommentResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/UpdateComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) DeleteComment(ctx context.Context, in *DeleteCommentRequest, opts ...grpc.CallOption) (*DeleteCommentResponse, error) {
+	out := new(DeleteCommentResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/DeleteComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*ListCommentsResponse, error) {
+	out := new(ListCommentsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ListComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ListPendingComments(ctx context.Context, in *ListPendingCommentsRequest, opts ...grpc.CallOption) (*ListPendingCommentsResponse, error) {
+	out := new(ListPendingCommentsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ListPendingComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ModerateComments(ctx context.Context, in *ModerateCommentsRequest, opts ...grpc.CallOption) (*ModerateCommentsResponse, error) {
+	out := new(ModerateCommentsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ModerateComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) AskQuestion(ctx context.Context, in *AskQuestionRequest, opts ...grpc.CallOption) (*AskQuestionResponse, error) {
+	out := new(AskQuestionResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/AskQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) AnswerQuestion(ctx context.Context, in *AnswerQuestionRequest, opts ...grpc.CallOption) (*AnswerQuestionResponse, error) {
+	out := new(AnswerQuestionResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/AnswerQuestion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ListQuestions(ctx context.Context, in *ListQuestionsRequest, opts ...grpc.CallOption) (*ListQuestionsResponse, error) {
+	out := new(ListQuestionsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ListQuestions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) UpvoteAnswer(ctx context.Context, in *UpvoteAnswerRequest, opts ...grpc.CallOption) (*UpvoteAnswerResponse, error) {
+	out := new(UpvoteAnswerResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/UpvoteAnswer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) GetProductRatingSummary(ctx context.Context, in *GetProductRatingSummaryRequest, opts ...grpc.CallOption) (*GetProductRatingSummaryResponse, error) {
+	out := new(GetProductRatingSummaryResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/GetProductRatingSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*CreateTagResponse, error) {
+	out := new(CreateTagResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/CreateTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) UpdateTag(ctx context.Context, in *UpdateTagRequest, opts ...grpc.CallOption) (*UpdateTagResponse, error) {
+	out := new(UpdateTagResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/UpdateTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error) {
+	out := new(ListTagsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ListTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) GetProductTagFrequencies(ctx context.Context, in *GetProductTagFrequenciesRequest, opts ...grpc.CallOption) (*GetProductTagFrequenciesResponse, error) {
+	out := new(GetProductTagFrequenciesResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/GetProductTagFrequencies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) SearchReviews(ctx context.Context, in *SearchReviewsRequest, opts ...grpc.CallOption) (*SearchReviewsResponse, error) {
+	out := new(SearchReviewsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/SearchReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) GetMyReviews(ctx context.Context, in *GetMyReviewsRequest, opts ...grpc.CallOption) (*GetMyReviewsResponse, error) {
+	out := new(GetMyReviewsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/GetMyReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) GetReviewsByUser(ctx context.Context, in *GetReviewsByUserRequest, opts ...grpc.CallOption) (*GetReviewsByUserResponse, error) {
+	out := new(GetReviewsByUserResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/GetReviewsByUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) GetReview(ctx context.Context, in *GetReviewRequest, opts ...grpc.CallOption) (*GetReviewResponse, error) {
+	out := new(GetReviewResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/GetReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) GetReviews(ctx context.Context, in *GetReviewsRequest, opts ...grpc.CallOption) (*GetReviewsResponse, error) {
+	out := new(GetReviewsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/GetReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) ListReviewableOrders(ctx context.Context, in *ListReviewableOrdersRequest, opts ...grpc.CallOption) (*ListReviewableOrdersResponse, error) {
+	out := new(ListReviewableOrdersResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ReviewService/ListReviewableOrders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewServiceClient) WatchProductReviews(ctx context.Context, in *WatchProductReviewsRequest, opts ...grpc.CallOption) (ReviewService_WatchProductReviewsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ReviewService_ServiceDesc.Streams[0], "/ecommerce.ReviewService/WatchProductReviews", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &reviewServiceWatchProductReviewsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReviewService_WatchProductReviewsClient interface {
+	Recv() (*ReviewEvent, error)
+	grpc.ClientStream
+}
+
+type reviewServiceWatchProductReviewsClient struct {
+	grpc.ClientStream
+}
+
+func (x *reviewServiceWatchProductReviewsClient) Recv() (*ReviewEvent, error) {
+	m := new(ReviewEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ReviewServiceServer is the server API for ReviewService service.
 // All implementations must embed UnimplementedReviewServiceServer
 // for forward compatibility
@@ -92,6 +419,40 @@ type ReviewServiceServer interface {
 	UpdateReview(context.Context, *UpdateReviewRequest) (*UpdateReviewResponse, error)
 	DeleteReview(context.Context, *DeleteReviewRequest) (*DeleteReviewResponse, error)
 	GetAllReviewByProductID(context.Context, *GetAllReviewByProductIDRequest) (*GetAllReviewByProductIDResponse, error)
+	ListPendingReviews(context.Context, *ListPendingReviewsRequest) (*ListPendingReviewsResponse, error)
+	ModerateReviews(context.Context, *ModerateReviewsRequest) (*ModerateReviewsResponse, error)
+	GetModerationHistory(context.Context, *GetModerationHistoryRequest) (*GetModerationHistoryResponse, error)
+	ReportReview(context.Context, *ReportReviewRequest) (*ReportReviewResponse, error)
+	ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error)
+	VoteReview(context.Context, *VoteReviewRequest) (*VoteReviewResponse, error)
+	RetractVote(context.Context, *RetractVoteRequest) (*RetractVoteResponse, error)
+	ReplyToReview(context.Context, *ReplyToReviewRequest) (*ReplyToReviewResponse, error)
+	CreateComment(context.Context, *CreateCommentRequest) (*CreateCommentResponse, error)
+	UpdateComment(context.Context, *UpdateCommentRequest) (*UpdateCommentResponse, error)
+	DeleteComment(context.Context, *DeleteCommentRequest) (*DeleteCommentResponse, error)
+	ListComments(context.Context, *ListCommentsRequest) (*ListCommentsResponse, error)
+	ListPendingComments(context.Context, *ListPendingCommentsRequest) (*ListPendingCommentsResponse, error)
+	ModerateComments(context.Context, *ModerateCommentsRequest) (*ModerateCommentsResponse, error)
+	AskQuestion(context.Context, *AskQuestionRequest) (*AskQuestionResponse, error)
+	AnswerQuestion(context.Context, *AnswerQuestionRequest) (*AnswerQuestionResponse, error)
+	ListQuestions(context.Context, *ListQuestionsRequest) (*ListQuestionsResponse, error)
+	UpvoteAnswer(context.Context, *UpvoteAnswerRequest) (*UpvoteAnswerResponse, error)
+	GetProductRatingSummary(context.Context, *GetProductRatingSummaryRequest) (*GetProductRatingSummaryResponse, error)
+	CreateTag(context.Context, *CreateTagRequest) (*CreateTagResponse, error)
+	UpdateTag(context.Context, *UpdateTagRequest) (*UpdateTagResponse, error)
+	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+	GetProductTagFrequencies(context.Context, *GetProductTagFrequenciesRequest) (*GetProductTagFrequenciesResponse, error)
+	SearchReviews(context.Context, *SearchReviewsRequest) (*SearchReviewsResponse, error)
+	GetMyReviews(context.Context, *GetMyReviewsRequest) (*GetMyReviewsResponse, error)
+	// admins only; reviews come without product details, no dependency
+	// resolves a product by id
+	GetReviewsByUser(context.Context, *GetReviewsByUserRequest) (*GetReviewsByUserResponse, error)
+	// returns published reviews, and unpublished ones to their author and
+	// admins when called with a token
+	GetReview(context.Context, *GetReviewRequest) (*GetReviewResponse, error)
+	GetReviews(context.Context, *GetReviewsRequest) (*GetReviewsResponse, error)
+	ListReviewableOrders(context.Context, *ListReviewableOrdersRequest) (*ListReviewableOrdersResponse, error)
+	WatchProductReviews(*WatchProductReviewsRequest, ReviewService_WatchProductReviewsServer) error
 	mustEmbedUnimplementedReviewServiceServer()
 }
 
@@ -114,6 +475,96 @@ func (UnimplementedReviewServiceServer) DeleteReview(context.Context, *DeleteRev
 func (UnimplementedReviewServiceServer) GetAllReviewByProductID(context.Context, *GetAllReviewByProductIDRequest) (*GetAllReviewByProductIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllReviewByProductID not implemented")
 }
+func (UnimplementedReviewServiceServer) ListPendingReviews(context.Context, *ListPendingReviewsRequest) (*ListPendingReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingReviews not implemented")
+}
+func (UnimplementedReviewServiceServer) ModerateReviews(context.Context, *ModerateReviewsRequest) (*ModerateReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReviews not implemented")
+}
+func (UnimplementedReviewServiceServer) GetModerationHistory(context.Context, *GetModerationHistoryRequest) (*GetModerationHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModerationHistory not implemented")
+}
+func (UnimplementedReviewServiceServer) ReportReview(context.Context, *ReportReviewRequest) (*ReportReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportReview not implemented")
+}
+func (UnimplementedReviewServiceServer) ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReports not implemented")
+}
+func (UnimplementedReviewServiceServer) VoteReview(context.Context, *VoteReviewRequest) (*VoteReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteReview not implemented")
+}
+func (UnimplementedReviewServiceServer) RetractVote(context.Context, *RetractVoteRequest) (*RetractVoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetractVote not implemented")
+}
+func (UnimplementedReviewServiceServer) ReplyToReview(context.Context, *ReplyToReviewRequest) (*ReplyToReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplyToReview not implemented")
+}
+func (UnimplementedReviewServiceServer) CreateComment(context.Context, *CreateCommentRequest) (*CreateCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateComment not implemented")
+}
+func (UnimplementedReviewServiceServer) UpdateComment(context.Context, *UpdateCommentRequest) (*UpdateCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateComment not implemented")
+}
+func (UnimplementedReviewServiceServer) DeleteComment(context.Context, *DeleteCommentRequest) (*DeleteCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteComment not implemented")
+}
+func (UnimplementedReviewServiceServer) ListComments(context.Context, *ListCommentsRequest) (*ListCommentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComments not implemented")
+}
+func (UnimplementedReviewServiceServer) ListPendingComments(context.Context, *ListPendingCommentsRequest) (*ListPendingCommentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingComments not implemented")
+}
+func (UnimplementedReviewServiceServer) ModerateComments(context.Context, *ModerateCommentsRequest) (*ModerateCommentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateComments not implemented")
+}
+func (UnimplementedReviewServiceServer) AskQuestion(context.Context, *AskQuestionRequest) (*AskQuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AskQuestion not implemented")
+}
+func (UnimplementedReviewServiceServer) AnswerQuestion(context.Context, *AnswerQuestionRequest) (*AnswerQuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnswerQuestion not implemented")
+}
+func (UnimplementedReviewServiceServer) ListQuestions(context.Context, *ListQuestionsRequest) (*ListQuestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQuestions not implemented")
+}
+func (UnimplementedReviewServiceServer) UpvoteAnswer(context.Context, *UpvoteAnswerRequest) (*UpvoteAnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpvoteAnswer not implemented")
+}
+func (UnimplementedReviewServiceServer) GetProductRatingSummary(context.Context, *GetProductRatingSummaryRequest) (*GetProductRatingSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductRatingSummary not implemented")
+}
+func (UnimplementedReviewServiceServer) CreateTag(context.Context, *CreateTagRequest) (*CreateTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedReviewServiceServer) UpdateTag(context.Context, *UpdateTagRequest) (*UpdateTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTag not implemented")
+}
+func (UnimplementedReviewServiceServer) ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedReviewServiceServer) GetProductTagFrequencies(context.Context, *GetProductTagFrequenciesRequest) (*GetProductTagFrequenciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductTagFrequencies not implemented")
+}
+func (UnimplementedReviewServiceServer) SearchReviews(context.Context, *SearchReviewsRequest) (*SearchReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchReviews not implemented")
+}
+func (UnimplementedReviewServiceServer) GetMyReviews(context.Context, *GetMyReviewsRequest) (*GetMyReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMyReviews not implemented")
+}
+func (UnimplementedReviewServiceServer) GetReviewsByUser(context.Context, *GetReviewsByUserRequest) (*GetReviewsByUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviewsByUser not implemented")
+}
+func (UnimplementedReviewServiceServer) GetReview(context.Context, *GetReviewRequest) (*GetReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReview not implemented")
+}
+func (UnimplementedReviewServiceServer) GetReviews(context.Context, *GetReviewsRequest) (*GetReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviews not implemented")
+}
+func (UnimplementedReviewServiceServer) ListReviewableOrders(context.Context, *ListReviewableOrdersRequest) (*ListReviewableOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviewableOrders not implemented")
+}
+func (UnimplementedReviewServiceServer) WatchProductReviews(*WatchProductReviewsRequest, ReviewService_WatchProductReviewsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProductReviews not implemented")
+}
 func (UnimplementedReviewServiceServer) mustEmbedUnimplementedReviewServiceServer() {}
 
 // UnsafeReviewServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -217,34 +668,699 @@ func _ReviewService_GetAllReviewByProductID_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
-// ReviewService_ServiceDesc is the grpc.ServiceDesc for ReviewService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var ReviewService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "ecommerce.ReviewService",
-	HandlerType: (*ReviewServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Ping",
-			Handler:    _ReviewService_Ping_Handler,
-		},
-		{
-			MethodName: "CreateReview",
-			Handler:    _ReviewService_CreateReview_Handler,
-		},
-		{
-			MethodName: "UpdateReview",
-			Handler:    _ReviewService_UpdateReview_Handler,
-		},
-		{
-			MethodName: "DeleteReview",
-			Handler:    _ReviewService_DeleteReview_Handler,
-		},
-		{
-			MethodName: "GetAllReviewByProductID",
-			Handler:    _ReviewService_GetAllReviewByProductID_Handler,
+func _ReviewService_ListPendingReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ListPendingReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ListPendingReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ListPendingReviews(ctx, req.(*ListPendingReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ModerateReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ModerateReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ModerateReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ModerateReviews(ctx, req.(*ModerateReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_GetModerationHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetModerationHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).GetModerationHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/GetModerationHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).GetModerationHistory(ctx, req.(*GetModerationHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ReportReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ReportReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ReportReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ReportReview(ctx, req.(*ReportReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ListReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ListReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ListReports",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ListReports(ctx, req.(*ListReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_VoteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).VoteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/VoteReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).VoteReview(ctx, req.(*VoteReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_RetractVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetractVoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).RetractVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/RetractVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).RetractVote(ctx, req.(*RetractVoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ReplyToReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplyToReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ReplyToReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ReplyToReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ReplyToReview(ctx, req.(*ReplyToReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_CreateComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).CreateComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/CreateComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).CreateComment(ctx, req.(*CreateCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_UpdateComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).UpdateComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/UpdateComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).UpdateComment(ctx, req.(*UpdateCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_DeleteComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).DeleteComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/DeleteComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).DeleteComment(ctx, req.(*DeleteCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ListComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ListComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ListComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ListComments(ctx, req.(*ListCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ListPendingComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ListPendingComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ListPendingComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ListPendingComments(ctx, req.(*ListPendingCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ModerateComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ModerateComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ModerateComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ModerateComments(ctx, req.(*ModerateCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_AskQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AskQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).AskQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/AskQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).AskQuestion(ctx, req.(*AskQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_AnswerQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnswerQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).AnswerQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/AnswerQuestion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).AnswerQuestion(ctx, req.(*AnswerQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ListQuestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ListQuestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ListQuestions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ListQuestions(ctx, req.(*ListQuestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_UpvoteAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpvoteAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).UpvoteAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/UpvoteAnswer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).UpvoteAnswer(ctx, req.(*UpvoteAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_GetProductRatingSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRatingSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).GetProductRatingSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/GetProductRatingSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).GetProductRatingSummary(ctx, req.(*GetProductRatingSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).CreateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/CreateTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).CreateTag(ctx, req.(*CreateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_UpdateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).UpdateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/UpdateTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).UpdateTag(ctx, req.(*UpdateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ListTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ListTags(ctx, req.(*ListTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_GetProductTagFrequencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductTagFrequenciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).GetProductTagFrequencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/GetProductTagFrequencies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).GetProductTagFrequencies(ctx, req.(*GetProductTagFrequenciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_SearchReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).SearchReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/SearchReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).SearchReviews(ctx, req.(*SearchReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_GetMyReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMyReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).GetMyReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/GetMyReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).GetMyReviews(ctx, req.(*GetMyReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_GetReviewsByUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReviewsByUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).GetReviewsByUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/GetReviewsByUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).GetReviewsByUser(ctx, req.(*GetReviewsByUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_GetReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).GetReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/GetReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).GetReview(ctx, req.(*GetReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_GetReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).GetReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/GetReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).GetReviews(ctx, req.(*GetReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_ListReviewableOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewableOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewServiceServer).ListReviewableOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ReviewService/ListReviewableOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewServiceServer).ListReviewableOrders(ctx, req.(*ListReviewableOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewService_WatchProductReviews_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProductReviewsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReviewServiceServer).WatchProductReviews(m, &reviewServiceWatchProductReviewsServer{stream})
+}
+
+type ReviewService_WatchProductReviewsServer interface {
+	Send(*ReviewEvent) error
+	grpc.ServerStream
+}
+
+type reviewServiceWatchProductReviewsServer struct {
+	grpc.ServerStream
+}
+
+func (x *reviewServiceWatchProductReviewsServer) Send(m *ReviewEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ReviewService_ServiceDesc is the grpc.ServiceDesc for ReviewService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReviewService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ecommerce.ReviewService",
+	HandlerType: (*ReviewServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _ReviewService_Ping_Handler,
+		},
+		{
+			MethodName: "CreateReview",
+			Handler:    _ReviewService_CreateReview_Handler,
+		},
+		{
+			MethodName: "UpdateReview",
+			Handler:    _ReviewService_UpdateReview_Handler,
+		},
+		{
+			MethodName: "DeleteReview",
+			Handler:    _ReviewService_DeleteReview_Handler,
+		},
+		{
+			MethodName: "GetAllReviewByProductID",
+			Handler:    _ReviewService_GetAllReviewByProductID_Handler,
+		},
+		{
+			MethodName: "ListPendingReviews",
+			Handler:    _ReviewService_ListPendingReviews_Handler,
+		},
+		{
+			MethodName: "ModerateReviews",
+			Handler:    _ReviewService_ModerateReviews_Handler,
+		},
+		{
+			MethodName: "GetModerationHistory",
+			Handler:    _ReviewService_GetModerationHistory_Handler,
+		},
+		{
+			MethodName: "ReportReview",
+			Handler:    _ReviewService_ReportReview_Handler,
+		},
+		{
+			MethodName: "ListReports",
+			Handler:    _ReviewService_ListReports_Handler,
+		},
+		{
+			MethodName: "VoteReview",
+			Handler:    _ReviewService_VoteReview_Handler,
+		},
+		{
+			MethodName: "RetractVote",
+			Handler:    _ReviewService_RetractVote_Handler,
+		},
+		{
+			MethodName: "ReplyToReview",
+			Handler:    _ReviewService_ReplyToReview_Handler,
+		},
+		{
+			MethodName: "CreateComment",
+			Handler:    _ReviewService_CreateComment_Handler,
+		},
+		{
+			MethodName: "UpdateComment",
+			Handler:    _ReviewService_UpdateComment_Handler,
+		},
+		{
+			MethodName: "DeleteComment",
+			Handler:    _ReviewService_DeleteComment_Handler,
+		},
+		{
+			MethodName: "ListComments",
+			Handler:    _ReviewService_ListComments_Handler,
+		},
+		{
+			MethodName: "ListPendingComments",
+			Handler:    _ReviewService_ListPendingComments_Handler,
+		},
+		{
+			MethodName: "ModerateComments",
+			Handler:    _ReviewService_ModerateComments_Handler,
+		},
+		{
+			MethodName: "AskQuestion",
+			Handler:    _ReviewService_AskQuestion_Handler,
+		},
+		{
+			MethodName: "AnswerQuestion",
+			Handler:    _ReviewService_AnswerQuestion_Handler,
+		},
+		{
+			MethodName: "ListQuestions",
+			Handler:    _ReviewService_ListQuestions_Handler,
+		},
+		{
+			MethodName: "UpvoteAnswer",
+			Handler:    _ReviewService_UpvoteAnswer_Handler,
+		},
+		{
+			MethodName: "GetProductRatingSummary",
+			Handler:    _ReviewService_GetProductRatingSummary_Handler,
+		},
+		{
+			MethodName: "CreateTag",
+			Handler:    _ReviewService_CreateTag_Handler,
+		},
+		{
+			MethodName: "UpdateTag",
+			Handler:    _ReviewService_UpdateTag_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _ReviewService_ListTags_Handler,
+		},
+		{
+			MethodName: "GetProductTagFrequencies",
+			Handler:    _ReviewService_GetProductTagFrequencies_Handler,
+		},
+		{
+			MethodName: "SearchReviews",
+			Handler:    _ReviewService_SearchReviews_Handler,
+		},
+		{
+			MethodName: "GetMyReviews",
+			Handler:    _ReviewService_GetMyReviews_Handler,
+		},
+		{
+			MethodName: "GetReviewsByUser",
+			Handler:    _ReviewService_GetReviewsByUser_Handler,
+		},
+		{
+			MethodName: "GetReview",
+			Handler:    _ReviewService_GetReview_Handler,
+		},
+		{
+			MethodName: "GetReviews",
+			Handler:    _ReviewService_GetReviews_Handler,
+		},
+		{
+			MethodName: "ListReviewableOrders",
+			Handler:    _ReviewService_ListReviewableOrders_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchProductReviews",
+			Handler:       _ReviewService_WatchProductReviews_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "review_service.proto",
 }
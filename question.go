@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+const maxQuestionLength = 1000
+
+func (srv reviewService) AskQuestion(ctx context.Context, req *pb.AskQuestionRequest) (*pb.AskQuestionResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := validateQuestionContent(req.GetContent())
+	if req.GetProductId() <= 0 {
+		violations = append(violations, fieldViolation{field: "product_id", description: msgProductIDInvalid})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+	if err := srv.refuseRejectedContent("content", req.GetContent()); err != nil {
+		return nil, err
+	}
+
+	question, err := srv.queries.InsertQuestion(ctx, repository.InsertQuestionParams{
+		ProductID: req.GetProductId(),
+		UserID:    caller.userID,
+		Content:   req.GetContent(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AskQuestionResponse{
+		Message:  localize(ctx, msgQuestionAsked),
+		Question: toPbQuestion(question),
+	}, nil
+}
+
+func (srv reviewService) AnswerQuestion(ctx context.Context, req *pb.AnswerQuestionRequest) (*pb.AnswerQuestionResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := validateQuestionContent(req.GetContent())
+	if req.GetQuestionId() <= 0 {
+		violations = append(violations, fieldViolation{field: "question_id", description: msgQuestionIDInvalid})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+	if err := srv.refuseRejectedContent("content", req.GetContent()); err != nil {
+		return nil, err
+	}
+
+	question, err := srv.queries.GetQuestion(ctx, req.GetQuestionId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound("question", req.GetQuestionId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// badges are checked with the caller's token, as order service only
+	// answers about the orders of its bearer
+	var verifiedBuyer, fromSupplier bool
+	switch caller.role {
+	case pb.UserRole_customer:
+		resp, err := srv.orderClient.CheckOrderIsHandled(forwardMetadata(ctx), &pb.CheckOrderIsHandledRequest{
+			ProductId: question.ProductID,
+		})
+		if err != nil {
+			return nil, fromDependency("order-service", err)
+		}
+		verifiedBuyer = resp.GetIsBought()
+	case pb.UserRole_supplier:
+		fromSupplier, err = srv.soldBy(ctx, question.ProductID, caller.userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var answer repository.QuestionAnswer
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		answer, err = q.InsertAnswer(ctx, repository.InsertAnswerParams{
+			QuestionID:    question.ID,
+			UserID:        caller.userID,
+			Content:       req.GetContent(),
+			VerifiedBuyer: verifiedBuyer,
+			FromSupplier:  fromSupplier,
+		})
+		if err != nil {
+			return err
+		}
+		return q.IncrementAnswerCount(ctx, question.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AnswerQuestionResponse{
+		Message: localize(ctx, msgQuestionAnswered),
+		Answer:  toPbAnswer(answer),
+	}, nil
+}
+
+func (srv reviewService) ListQuestions(ctx context.Context, req *pb.ListQuestionsRequest) (*pb.ListQuestionsResponse, error) {
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	questions, err := srv.queries.ListQuestionsByProductID(ctx, repository.ListQuestionsByProductIDParams{
+		ProductID: req.GetProductId(),
+		AfterID:   afterID,
+		Limit:     pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return &pb.ListQuestionsResponse{}, nil
+	}
+
+	result := make([]*pb.Question, 0, len(questions))
+	byID := make(map[int64]*pb.Question, len(questions))
+	ids := make([]int64, 0, len(questions))
+	for _, question := range questions {
+		pbQuestion := toPbQuestion(question)
+		result = append(result, pbQuestion)
+		byID[question.ID] = pbQuestion
+		ids = append(ids, question.ID)
+	}
+
+	answers, err := srv.queries.ListAnswersByQuestionIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, answer := range answers {
+		question := byID[answer.QuestionID]
+		question.ListAnswer = append(question.ListAnswer, toPbAnswer(answer))
+	}
+
+	return &pb.ListQuestionsResponse{
+		ListQuestion:  result,
+		NextPageToken: nextPageToken(len(questions), pageSize, questions[len(questions)-1].ID),
+	}, nil
+}
+
+func (srv reviewService) UpvoteAnswer(ctx context.Context, req *pb.UpvoteAnswerRequest) (*pb.UpvoteAnswerResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := srv.queries.GetAnswer(ctx, req.GetAnswerId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound("answer", req.GetAnswerId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if answer.UserID == caller.userID {
+		return nil, errForbidden("OWN_ANSWER")
+	}
+
+	// upvoting twice keeps the first upvote
+	count := answer.UpvoteCount
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		inserted, err := q.InsertAnswerUpvote(ctx, repository.InsertAnswerUpvoteParams{
+			AnswerID: answer.ID,
+			UserID:   caller.userID,
+		})
+		if err != nil || inserted == 0 {
+			return err
+		}
+		count, err = q.IncrementUpvoteCount(ctx, answer.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpvoteAnswerResponse{
+		Message:     localize(ctx, msgAnswerUpvoted),
+		UpvoteCount: count,
+	}, nil
+}
+
+func validateQuestionContent(content string) []fieldViolation {
+	var violations []fieldViolation
+	if strings.TrimSpace(content) == "" {
+		violations = append(violations, fieldViolation{field: "content", description: msgContentEmpty})
+	}
+	if utf8.RuneCountInString(content) > maxQuestionLength {
+		violations = append(violations, fieldViolation{field: "content", description: msgContentLength, args: []interface{}{maxQuestionLength}})
+	}
+	return violations
+}
+
+func toPbQuestion(question repository.ProductQuestion) *pb.Question {
+	return &pb.Question{
+		QuestionId:  question.ID,
+		ProductId:   question.ProductID,
+		UserId:      question.UserID,
+		Content:     question.Content,
+		AnswerCount: question.AnswerCount,
+		CreatedAt:   question.CreatedAt.Unix(),
+	}
+}
+
+func toPbAnswer(answer repository.QuestionAnswer) *pb.Answer {
+	return &pb.Answer{
+		AnswerId:      answer.ID,
+		QuestionId:    answer.QuestionID,
+		UserId:        answer.UserID,
+		Content:       answer.Content,
+		VerifiedBuyer: answer.VerifiedBuyer,
+		FromSupplier:  answer.FromSupplier,
+		UpvoteCount:   answer.UpvoteCount,
+		CreatedAt:     answer.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// rateLimit is a token bucket holding up to burst tokens, refilled at
+// burst tokens per period. The zero value means unlimited.
+type rateLimit struct {
+	burst  int
+	period time.Duration
+}
+
+func (l rateLimit) unlimited() bool {
+	return l.burst <= 0 || l.period <= 0
+}
+
+// perSecond returns the refill rate in tokens per second.
+func (l rateLimit) perSecond() float64 {
+	return float64(l.burst) / l.period.Seconds()
+}
+
+// parseRateLimit parses limits such as "5/1m" (5 requests per minute).
+func parseRateLimit(s string) (rateLimit, error) {
+	n, d, ok := strings.Cut(s, "/")
+	if !ok {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q", s)
+	}
+	burst, err := strconv.Atoi(n)
+	if err != nil {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+	period, err := time.ParseDuration(d)
+	if err != nil {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+	return rateLimit{burst: burst, period: period}, nil
+}
+
+// methodLimit holds the limits of one rpc, applied per user and per client ip.
+type methodLimit struct {
+	perUser rateLimit
+	perIP   rateLimit
+}
+
+// defaultMethodLimits are the limits of rate limited rpcs. Each can be
+// overridden with RATE_LIMIT_<METHOD>_USER and RATE_LIMIT_<METHOD>_IP, e.g.
+// RATE_LIMIT_CREATE_REVIEW_USER=5/1m.
+var defaultMethodLimits = map[string]methodLimit{
+	"/ecommerce.ReviewService/CreateReview": {
+		perUser: rateLimit{burst: 5, period: time.Minute},
+		perIP:   rateLimit{burst: 20, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/UpdateReview": {
+		perUser: rateLimit{burst: 10, period: time.Minute},
+		perIP:   rateLimit{burst: 30, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/DeleteReview": {
+		perUser: rateLimit{burst: 10, period: time.Minute},
+		perIP:   rateLimit{burst: 30, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/ReportReview": {
+		perUser: rateLimit{burst: 10, period: time.Hour},
+		perIP:   rateLimit{burst: 30, period: time.Hour},
+	},
+	"/ecommerce.ReviewService/VoteReview": {
+		perUser: rateLimit{burst: 30, period: time.Minute},
+		perIP:   rateLimit{burst: 100, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/RetractVote": {
+		perUser: rateLimit{burst: 30, period: time.Minute},
+		perIP:   rateLimit{burst: 100, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/ReplyToReview": {
+		perUser: rateLimit{burst: 10, period: time.Minute},
+		perIP:   rateLimit{burst: 30, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/CreateComment": {
+		perUser: rateLimit{burst: 10, period: time.Minute},
+		perIP:   rateLimit{burst: 30, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/UpdateComment": {
+		perUser: rateLimit{burst: 10, period: time.Minute},
+		perIP:   rateLimit{burst: 30, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/DeleteComment": {
+		perUser: rateLimit{burst: 10, period: time.Minute},
+		perIP:   rateLimit{burst: 30, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/AskQuestion": {
+		perUser: rateLimit{burst: 5, period: time.Minute},
+		perIP:   rateLimit{burst: 20, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/AnswerQuestion": {
+		perUser: rateLimit{burst: 10, period: time.Minute},
+		perIP:   rateLimit{burst: 30, period: time.Minute},
+	},
+	"/ecommerce.ReviewService/UpvoteAnswer": {
+		perUser: rateLimit{burst: 30, period: time.Minute},
+		perIP:   rateLimit{burst: 100, period: time.Minute},
+	},
+	// searches are public, so only limited per client ip
+	"/ecommerce.ReviewService/SearchReviews": {
+		perIP: rateLimit{burst: 60, period: time.Minute},
+	},
+}
+
+var camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// methodLimitsFromEnv returns defaultMethodLimits with the env overrides applied.
+func methodLimitsFromEnv() (map[string]methodLimit, error) {
+	limits := make(map[string]methodLimit, len(defaultMethodLimits))
+	for method, limit := range defaultMethodLimits {
+		name := method[strings.LastIndex(method, "/")+1:]
+		prefix := "RATE_LIMIT_" + strings.ToUpper(camelBoundary.ReplaceAllString(name, "${1}_${2}"))
+		for suffix, target := range map[string]*rateLimit{"_USER": &limit.perUser, "_IP": &limit.perIP} {
+			v := os.Getenv(prefix + suffix)
+			if v == "" {
+				continue
+			}
+			parsed, err := parseRateLimit(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s%s: %w", prefix, suffix, err)
+			}
+			*target = parsed
+		}
+		limits[method] = limit
+	}
+	return limits, nil
+}
+
+// limiterBackend stores the token buckets. take consumes a token of the
+// bucket identified by key and, when none is left, reports how long until
+// the next one is available. refund gives back a token taken by a call that
+// was rejected later on.
+type limiterBackend interface {
+	take(ctx context.Context, key string, limit rateLimit) (allowed bool, retryAfter time.Duration, err error)
+	refund(ctx context.Context, key string, limit rateLimit) error
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// memoryLimiter keeps the buckets in memory, so limits apply per replica.
+// Buckets unused for ttl are evicted.
+type memoryLimiter struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func newMemoryLimiter(ttl time.Duration) *memoryLimiter {
+	return &memoryLimiter{
+		ttl:     ttl,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (m *memoryLimiter) take(_ context.Context, key string, limit rateLimit) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit.burst), last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * limit.perSecond()
+	if b.tokens > float64(limit.burst) {
+		b.tokens = float64(limit.burst)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	wait := time.Duration((1 - b.tokens) / limit.perSecond() * float64(time.Second))
+	return false, wait, nil
+}
+
+func (m *memoryLimiter) refund(_ context.Context, key string, limit rateLimit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok := m.buckets[key]; ok {
+		b.tokens++
+		if b.tokens > float64(limit.burst) {
+			b.tokens = float64(limit.burst)
+		}
+	}
+	return nil
+}
+
+// run evicts idle buckets every interval until ctx is done.
+func (m *memoryLimiter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.evict()
+		}
+	}
+}
+
+func (m *memoryLimiter) evict() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for key, b := range m.buckets {
+		if now.Sub(b.last) > m.ttl {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// rateLimiter enforces the per-method limits. Client ips are limited before
+// authentication, so that floods of unauthenticated calls don't reach
+// auth-service, and users after it.
+type rateLimiter struct {
+	backend limiterBackend
+	limits  map[string]methodLimit
+	// trustedProxies is the number of proxies in front of the service that
+	// append the address they received the call from to x-forwarded-for.
+	// Zero ignores the header, which callers can set to anything
+	trustedProxies int
+}
+
+// checkIP takes a token of the caller's ip bucket. Calls failing
+// authentication afterwards keep it spent.
+func (rl rateLimiter) checkIP(ctx context.Context, method string) error {
+	key, limit, ok := rl.ipBucket(ctx, method)
+	if !ok {
+		return nil
+	}
+	return rl.take(ctx, key, limit)
+}
+
+// checkUser takes a token of the caller's user bucket. When the user is
+// limited, the token checkIP took is given back so that the rejected call
+// only counts against one of the buckets.
+func (rl rateLimiter) checkUser(ctx context.Context, method string) error {
+	limit, ok := rl.limits[method]
+	if !ok || limit.perUser.unlimited() {
+		return nil
+	}
+	p, ok := principalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	err := rl.take(ctx, fmt.Sprintf("user:%d:%s", p.userID, method), limit.perUser)
+	if err != nil {
+		if key, ipLimit, ok := rl.ipBucket(ctx, method); ok {
+			if refundErr := rl.backend.refund(ctx, key, ipLimit); refundErr != nil {
+				log.Printf("rate limiter: %s: %v", key, refundErr)
+			}
+		}
+	}
+	return err
+}
+
+func (rl rateLimiter) ipBucket(ctx context.Context, method string) (string, rateLimit, bool) {
+	limit, ok := rl.limits[method]
+	if !ok || limit.perIP.unlimited() {
+		return "", rateLimit{}, false
+	}
+	ip := rl.clientIP(ctx)
+	if ip == "" {
+		return "", rateLimit{}, false
+	}
+	return fmt.Sprintf("ip:%s:%s", ip, method), limit.perIP, true
+}
+
+func (rl rateLimiter) take(ctx context.Context, key string, limit rateLimit) error {
+	allowed, retryAfter, err := rl.backend.take(ctx, key, limit)
+	if err != nil {
+		// fail open, the limiter must not take the service down
+		log.Printf("rate limiter: %s: %v", key, err)
+		return nil
+	}
+	if !allowed {
+		return errRateLimited(retryAfter)
+	}
+	return nil
+}
+
+// clientIP returns the caller address, from x-forwarded-for when behind
+// trusted proxies.
+func (rl rateLimiter) clientIP(ctx context.Context) string {
+	if rl.trustedProxies > 0 {
+		if ip := forwardedFor(ctx, rl.trustedProxies); ip != "" {
+			return ip
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// forwardedFor returns the address the outermost of the trusted proxies
+// received the call from. Entries on its left were sent by the caller and
+// can't be trusted.
+func forwardedFor(ctx context.Context, trustedProxies int) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-forwarded-for")
+	if len(values) == 0 {
+		return ""
+	}
+	entries := strings.Split(strings.Join(values, ","), ",")
+	i := len(entries) - trustedProxies
+	if i < 0 {
+		// fewer entries than proxies, all of them were set by the proxies
+		i = 0
+	}
+	return strings.TrimSpace(entries[i])
+}
+
+// ipUnaryInterceptor must run before the auth interceptor.
+func (rl rateLimiter) ipUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := rl.checkIP(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// userUnaryInterceptor must run after the auth interceptor to see the principal.
+func (rl rateLimiter) userUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := rl.checkUser(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
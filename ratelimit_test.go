@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// countingAuthenticator resolves every caller to user, or fails with err.
+type countingAuthenticator struct {
+	calls int
+	user  int64
+	err   error
+}
+
+func (a *countingAuthenticator) authenticate(_ context.Context, _ accessPolicy) (principal, error) {
+	a.calls++
+	if a.err != nil {
+		return principal{}, a.err
+	}
+	return principal{userID: a.user}, nil
+}
+
+const createReviewMethod = "/ecommerce.ReviewService/CreateReview"
+
+func testLimiter(perUser, perIP int) rateLimiter {
+	return rateLimiter{
+		backend: newMemoryLimiter(time.Hour),
+		limits: map[string]methodLimit{
+			createReviewMethod: {
+				perUser: rateLimit{burst: perUser, period: time.Hour},
+				perIP:   rateLimit{burst: perIP, period: time.Hour},
+			},
+		},
+	}
+}
+
+// callThrough runs a call through the interceptor chain of main.
+func callThrough(rl rateLimiter, auth authenticator, ip string) error {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}})
+	info := &grpc.UnaryServerInfo{FullMethod: createReviewMethod}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	chain := []grpc.UnaryServerInterceptor{rl.ipUnaryInterceptor, authUnaryInterceptor(auth), rl.userUnaryInterceptor}
+	for i := len(chain) - 1; i >= 0; i-- {
+		interceptor, next := chain[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	_, err := handler(ctx, nil)
+	return err
+}
+
+func TestRateLimiterLimitsIPBeforeAuthentication(t *testing.T) {
+	rl := testLimiter(5, 2)
+	auth := &countingAuthenticator{err: status.Error(codes.Unauthenticated, "invalid token")}
+
+	for i := 0; i < 5; i++ {
+		_ = callThrough(rl, auth, "10.0.0.1")
+	}
+	if auth.calls != 2 {
+		t.Errorf("authenticator called %d times, want 2", auth.calls)
+	}
+	if err := callThrough(rl, auth, "10.0.0.2"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("other ip got %v, want Unauthenticated", err)
+	}
+}
+
+func TestRateLimiterRefundsIPWhenUserIsLimited(t *testing.T) {
+	rl := testLimiter(1, 2)
+
+	if err := callThrough(rl, &countingAuthenticator{user: 1}, "10.0.0.1"); err != nil {
+		t.Fatalf("first call rejected: %v", err)
+	}
+	err := callThrough(rl, &countingAuthenticator{user: 1}, "10.0.0.1")
+	var limited *reviewError
+	if !errors.As(err, &limited) || limited.kind != kindRateLimited {
+		t.Fatalf("second call of user got %v, want rate limited", err)
+	}
+	// the ip token of the rejected call was given back
+	if err := callThrough(rl, &countingAuthenticator{user: 2}, "10.0.0.1"); err != nil {
+		t.Errorf("other user on the same ip rejected: %v", err)
+	}
+	if err := callThrough(rl, &countingAuthenticator{user: 3}, "10.0.0.1"); err == nil {
+		t.Error("ip limit not enforced")
+	}
+}
+
+func TestClientIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	proxy := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 4000}}
+	tests := []struct {
+		name           string
+		trustedProxies int
+		forwardedFor   []string
+		want           string
+	}{
+		{"untrusted header", 0, []string{"203.0.113.7"}, "10.0.0.2"},
+		{"one proxy", 1, []string{"203.0.113.7"}, "203.0.113.7"},
+		{"spoofed by caller", 1, []string{"1.2.3.4, 203.0.113.7"}, "203.0.113.7"},
+		{"spoofed behind two proxies", 2, []string{"1.2.3.4, 203.0.113.7, 10.0.0.1"}, "203.0.113.7"},
+		{"spoofed in another header", 1, []string{"1.2.3.4", "203.0.113.7"}, "203.0.113.7"},
+		{"fewer entries than proxies", 2, []string{"203.0.113.7"}, "203.0.113.7"},
+		{"no header", 1, nil, "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), proxy)
+			md := metadata.MD{}
+			for _, v := range tt.forwardedFor {
+				md.Append("x-forwarded-for", v)
+			}
+			ctx = metadata.NewIncomingContext(ctx, md)
+			rl := rateLimiter{trustedProxies: tt.trustedProxies}
+			if got := rl.clientIP(ctx); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
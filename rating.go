@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+// ratingDimensions lists the sub-rating dimensions accepted for a review,
+// by product category.
+type ratingDimensions struct {
+	// Default applies to categories without their own set.
+	Default []string `json:"default"`
+	// Categories maps a category id to its dimensions.
+	Categories map[string][]string `json:"categories"`
+}
+
+var defaultRatingDimensions = ratingDimensions{
+	Default: []string{"quality", "value", "shipping", "as_described"},
+}
+
+// loadRatingDimensions reads a JSON file such as
+//
+//	{"default": ["quality", "value"], "categories": {"12": ["quality", "fit"]}}
+func loadRatingDimensions(path string) (ratingDimensions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ratingDimensions{}, err
+	}
+	var dims ratingDimensions
+	if err := json.Unmarshal(data, &dims); err != nil {
+		return ratingDimensions{}, fmt.Errorf("parse rating dimensions: %w", err)
+	}
+	for category := range dims.Categories {
+		if _, err := strconv.ParseInt(category, 10, 64); err != nil {
+			return ratingDimensions{}, fmt.Errorf("invalid category id %q", category)
+		}
+	}
+	return dims, nil
+}
+
+// forCategory returns the dimensions accepted for the category.
+func (d ratingDimensions) forCategory(categoryID int64) []string {
+	if dims, ok := d.Categories[strconv.FormatInt(categoryID, 10)]; ok {
+		return dims
+	}
+	return d.Default
+}
+
+// validateSubRatings checks that each dimension is rated at most once, from
+// 1 to 5, and belongs to the category. The category comes from the client and
+// can't be verified, so this only keeps honest clients consistent.
+func (d ratingDimensions) validateSubRatings(categoryID int64, subRatings []*pb.SubRating) []fieldViolation {
+	allowed := make(map[string]bool)
+	for _, dimension := range d.forCategory(categoryID) {
+		allowed[dimension] = true
+	}
+
+	var violations []fieldViolation
+	seen := make(map[string]bool, len(subRatings))
+	for i, subRating := range subRatings {
+		switch dimension := subRating.GetDimension(); {
+		case !allowed[dimension]:
+			violations = append(violations, fieldViolation{field: fmt.Sprintf("sub_ratings[%d].dimension", i), description: msgDimensionInvalid})
+		case seen[dimension]:
+			violations = append(violations, fieldViolation{field: fmt.Sprintf("sub_ratings[%d].dimension", i), description: msgDimensionDuplicated})
+		}
+		seen[subRating.GetDimension()] = true
+		if subRating.GetRating() < 1 || subRating.GetRating() > 5 {
+			violations = append(violations, fieldViolation{field: fmt.Sprintf("sub_ratings[%d].rating", i), description: msgNumStarRange, args: []interface{}{1, 5}})
+		}
+	}
+	return violations
+}
+
+func (srv reviewService) GetProductRatingSummary(ctx context.Context, req *pb.GetProductRatingSummaryRequest) (*pb.GetProductRatingSummaryResponse, error) {
+	rating, err := srv.queries.GetProductRating(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	subRatings, err := srv.queries.GetProductSubRatings(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetProductRatingSummaryResponse{
+		ProductId:   req.GetProductId(),
+		ReviewCount: rating.ReviewCount,
+		AverageStar: rating.AverageStar,
+	}
+	for _, subRating := range subRatings {
+		resp.ListDimension = append(resp.ListDimension, &pb.DimensionRating{
+			Dimension:     subRating.Dimension,
+			AverageRating: subRating.AverageRating,
+			RatingCount:   subRating.RatingCount,
+		})
+	}
+	return resp, nil
+}
+
+func toPbSubRatings(subRatings []repository.ReviewSubRating) []*pb.SubRating {
+	result := make([]*pb.SubRating, 0, len(subRatings))
+	for _, subRating := range subRatings {
+		result = append(result, &pb.SubRating{
+			Dimension: subRating.Dimension,
+			Rating:    subRating.Rating,
+		})
+	}
+	return result
+}
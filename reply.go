@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+const maxReplyLength = 2000
+
+func (srv reviewService) ReplyToReview(ctx context.Context, req *pb.ReplyToReviewRequest) (*pb.ReplyToReviewResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []fieldViolation
+	if req.GetReviewId() <= 0 {
+		violations = append(violations, fieldViolation{field: "review_id", description: msgReviewIDInvalid})
+	}
+	if strings.TrimSpace(req.GetContent()) == "" {
+		violations = append(violations, fieldViolation{field: "content", description: msgContentEmpty})
+	}
+	if utf8.RuneCountInString(req.GetContent()) > maxReplyLength {
+		violations = append(violations, fieldViolation{field: "content", description: msgContentLength, args: []interface{}{maxReplyLength}})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	if err := srv.refuseRejectedContent("content", req.GetContent()); err != nil {
+		return nil, err
+	}
+
+	review, err := srv.queries.GetReviewByID(ctx, req.GetReviewId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound("review", req.GetReviewId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != repository.ReviewStatusApproved {
+		return nil, errNotFound("review", req.GetReviewId())
+	}
+
+	sold, err := srv.soldBy(ctx, review.ProductID, caller.userID)
+	if err != nil {
+		return nil, err
+	}
+	if !sold {
+		return nil, errForbidden("NOT_PRODUCT_SELLER")
+	}
+
+	reply, err := srv.queries.UpsertReviewReply(ctx, repository.UpsertReviewReplyParams{
+		ReviewID:   review.ID,
+		SupplierID: caller.userID,
+		Content:    req.GetContent(),
+	})
+	// the existing reply belongs to another supplier
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errForbidden("NOT_REPLY_AUTHOR")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReplyToReviewResponse{
+		Message: localize(ctx, msgReplySaved),
+		Reply:   toPbReply(reply),
+	}, nil
+}
+
+// soldBy reports whether the supplier has handled an order of the product.
+// Orders are listed by order service for the supplier of the forwarded token.
+func (srv reviewService) soldBy(ctx context.Context, productID, supplierID int64) (bool, error) {
+	resp, err := srv.orderClient.GetHandledOrderBySupllier(forwardMetadata(ctx), &empty.Empty{})
+	if err != nil {
+		return false, fromDependency("order-service", err)
+	}
+	for _, order := range resp.GetListOrder() {
+		if order.GetProductId() == productID && order.GetSupplierId() == supplierID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func toPbReply(reply repository.ReviewReply) *pb.ReviewReply {
+	return &pb.ReviewReply{
+		SupplierId: reply.SupplierID,
+		Content:    reply.Content,
+		CreatedAt:  reply.CreatedAt.Unix(),
+		UpdatedAt:  reply.UpdatedAt.Unix(),
+	}
+}
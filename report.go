@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+const maxReportCommentLength = 500
+
+func (srv reviewService) ReportReview(ctx context.Context, req *pb.ReportReviewRequest) (*pb.ReportReviewResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []fieldViolation
+	if req.GetReviewId() <= 0 {
+		violations = append(violations, fieldViolation{field: "review_id", description: msgReviewIDInvalid})
+	}
+	if req.GetReason() == pb.ModerationReason_no_reason {
+		violations = append(violations, fieldViolation{field: "reason", description: msgReportReasonRequired})
+	}
+	if utf8.RuneCountInString(req.GetComment()) > maxReportCommentLength {
+		violations = append(violations, fieldViolation{field: "comment", description: msgReportCommentLength, args: []interface{}{maxReportCommentLength}})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		// the lock serializes reports of a review so the threshold is crossed once
+		review, err := q.GetReviewForUpdate(ctx, req.GetReviewId())
+		if errors.Is(err, sql.ErrNoRows) {
+			return errNotFound("review", req.GetReviewId())
+		}
+		if err != nil {
+			return err
+		}
+		// only published reviews can be reported
+		if review.Status != repository.ReviewStatusApproved {
+			return errNotFound("review", req.GetReviewId())
+		}
+		if review.UserID == caller.userID {
+			return errForbidden("OWN_REVIEW")
+		}
+
+		inserted, err := q.InsertReviewReport(ctx, repository.InsertReviewReportParams{
+			ReviewID: review.ID,
+			UserID:   caller.userID,
+			Reason:   req.GetReason().String(),
+			Comment:  req.GetComment(),
+		})
+		if err != nil {
+			return err
+		}
+		if inserted == 0 {
+			return errAlreadyReported(review.ID)
+		}
+
+		// reports handled by an earlier moderation decision are not counted again
+		count, err := q.CountReportsSince(ctx, repository.CountReportsSinceParams{
+			ReviewID: review.ID,
+			Since:    review.ModeratedAt.Time,
+		})
+		if err != nil {
+			return err
+		}
+		if srv.reportThreshold <= 0 || count < int64(srv.reportThreshold) {
+			return nil
+		}
+		return changeReviewStatus(ctx, q, review, statusChange{
+			to:     repository.ReviewStatusHidden,
+			reason: req.GetReason(),
+			note:   fmt.Sprintf("hidden after %d reports", count),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReportReviewResponse{
+		Message: localize(ctx, msgReviewReported),
+	}, nil
+}
+
+func (srv reviewService) ListReports(ctx context.Context, req *pb.ListReportsRequest) (*pb.ListReportsResponse, error) {
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	groups, err := srv.queries.ListReportedReviews(ctx, repository.ListReportedReviewsParams{
+		AfterID: afterID,
+		Limit:   pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return &pb.ListReportsResponse{}, nil
+	}
+
+	ids := make([]int64, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ReviewID)
+	}
+	reviews, err := srv.queries.GetReviewsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	pbReviews, err := srv.toPbReviews(ctx, reviews)
+	if err != nil {
+		return nil, err
+	}
+	reviewByID := make(map[int64]*pb.Review, len(pbReviews))
+	for _, review := range pbReviews {
+		reviewByID[review.GetReviewId()] = review
+	}
+
+	reports, err := srv.queries.ListReportsByReviewIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	reportsByReview := make(map[int64][]*pb.ReviewReport, len(groups))
+	for _, report := range reports {
+		reportsByReview[report.ReviewID] = append(reportsByReview[report.ReviewID], &pb.ReviewReport{
+			ReportId:  report.ID,
+			UserId:    report.UserID,
+			Reason:    pb.ModerationReason(pb.ModerationReason_value[report.Reason]),
+			Comment:   report.Comment,
+			CreatedAt: report.CreatedAt.Unix(),
+		})
+	}
+
+	result := make([]*pb.ReportedReview, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, &pb.ReportedReview{
+			Review:         reviewByID[group.ReviewID],
+			ReportCount:    group.ReportCount,
+			LastReportedAt: group.LastReportedAt.Unix(),
+			ListReport:     reportsByReview[group.ReviewID],
+		})
+	}
+	return &pb.ListReportsResponse{
+		ListReportedReview: result,
+		NextPageToken:      nextPageToken(len(groups), pageSize, groups[len(groups)-1].ReviewID),
+	}, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+var reviewColumns = []string{
+	"id", "user_id", "product_id", "num_star", "content", "status", "moderation_reason", "moderator_id",
+	"moderated_at", "created_at", "helpful_count", "not_helpful_count", "comment_count", "search_vector", "category_id",
+}
+
+// fakeModerationDB serves the queries of reading, reporting and moderating
+// reviews from memory. Statements apply immediately, transactions only group
+// them.
+type fakeModerationDB struct {
+	mu      sync.Mutex
+	reviews []repository.Review
+	reports map[int64]int64
+}
+
+func (db *fakeModerationDB) Connect(context.Context) (driver.Conn, error) {
+	return fakeModerationConn{db: db}, nil
+}
+
+func (db *fakeModerationDB) Driver() driver.Driver {
+	return nil
+}
+
+func (db *fakeModerationDB) service(reportThreshold int) reviewService {
+	conn := sql.OpenDB(db)
+	return reviewService{db: conn, queries: repository.New(conn), reportThreshold: reportThreshold}
+}
+
+func (db *fakeModerationDB) review(id int64) *repository.Review {
+	for i := range db.reviews {
+		if db.reviews[i].ID == id {
+			return &db.reviews[i]
+		}
+	}
+	return nil
+}
+
+type fakeModerationConn struct {
+	db *fakeModerationDB
+}
+
+func (c fakeModerationConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (c fakeModerationConn) Close() error              { return nil }
+func (c fakeModerationConn) Begin() (driver.Tx, error) { return c, nil }
+func (c fakeModerationConn) Commit() error             { return nil }
+func (c fakeModerationConn) Rollback() error           { return nil }
+
+var queryName = regexp.MustCompile(`-- name: (\w+)`)
+
+func (c fakeModerationConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	switch name := queryName.FindStringSubmatch(query)[1]; name {
+	case "GetReviewByID", "GetReviewForUpdate":
+		rows := &fakeRows{columns: reviewColumns}
+		if review := c.db.review(args[0].Value.(int64)); review != nil {
+			rows.values = append(rows.values, reviewValues(*review))
+		}
+		return rows, nil
+	case "CountReportsSince":
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{c.db.reports[args[0].Value.(int64)]}}}, nil
+	case "ListReviewsAwaitingModeration":
+		rows := &fakeRows{columns: reviewColumns}
+		for _, review := range c.db.reviews {
+			if review.Status == repository.ReviewStatusPending || (review.Status == repository.ReviewStatusHidden && !review.ModeratorID.Valid) {
+				rows.values = append(rows.values, reviewValues(review))
+			}
+		}
+		return rows, nil
+	case "GetRepliesByReviewIDs", "GetSubRatingsByReviewIDs", "GetTagsByReviewIDs", "GetImagesByOrderID":
+		return &fakeRows{}, nil
+	default:
+		return nil, fmt.Errorf("unexpected query %s", name)
+	}
+}
+
+func (c fakeModerationConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	switch name := queryName.FindStringSubmatch(query)[1]; name {
+	case "InsertReviewReport":
+		c.db.reports[args[0].Value.(int64)]++
+		return driver.RowsAffected(1), nil
+	case "UpdateReviewStatus":
+		review := c.db.review(args[0].Value.(int64))
+		review.Status = repository.ReviewStatus(args[1].Value.(string))
+		review.ModeratorID = sql.NullInt64{}
+		if id, ok := args[3].Value.(int64); ok {
+			review.ModeratorID = sql.NullInt64{Int64: id, Valid: true}
+		}
+		return driver.RowsAffected(1), nil
+	case "InsertModerationEvent", "InsertOutboxEvent", "NotifyReviewEvent":
+		return driver.RowsAffected(1), nil
+	default:
+		return nil, fmt.Errorf("unexpected query %s", name)
+	}
+}
+
+func reviewValues(r repository.Review) []driver.Value {
+	var moderatorID driver.Value
+	if r.ModeratorID.Valid {
+		moderatorID = r.ModeratorID.Int64
+	}
+	return []driver.Value{
+		r.ID, r.UserID, r.ProductID, int64(r.NumStar), r.Content, string(r.Status), r.ModerationReason, moderatorID,
+		nil, r.CreatedAt, int64(0), int64(0), int64(0), nil, r.CategoryID,
+	}
+}
+
+func TestAutoHiddenReviewAwaitsModeration(t *testing.T) {
+	db := &fakeModerationDB{
+		reviews: []repository.Review{
+			{ID: 1, UserID: 10, ProductID: 5, NumStar: 1, Status: repository.ReviewStatusApproved, CreatedAt: time.Now()},
+			{ID: 2, UserID: 11, ProductID: 5, NumStar: 4, Status: repository.ReviewStatusPending, CreatedAt: time.Now()},
+			// hidden by a moderator, who already decided on it
+			{ID: 3, UserID: 12, ProductID: 5, NumStar: 2, Status: repository.ReviewStatusHidden, ModeratorID: sql.NullInt64{Int64: 1, Valid: true}, CreatedAt: time.Now()},
+		},
+		reports: map[int64]int64{},
+	}
+	srv := db.service(2)
+
+	for _, reporter := range []int64{20, 21} {
+		ctx := withPrincipal(context.Background(), principal{userID: reporter, role: pb.UserRole_customer})
+		if _, err := srv.ReportReview(ctx, &pb.ReportReviewRequest{ReviewId: 1, Reason: pb.ModerationReason_spam}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if status := db.review(1).Status; status != repository.ReviewStatusHidden {
+		t.Fatalf("reported review is %s, want hidden", status)
+	}
+
+	admin := withPrincipal(context.Background(), principal{userID: 1, role: pb.UserRole_admin})
+	resp, err := srv.ListPendingReviews(admin, &pb.ListPendingReviewsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, review := range resp.GetListReview() {
+		ids = append(ids, review.GetReviewId())
+	}
+	if want := []int64{1, 2}; !equalIDs(ids, want) {
+		t.Errorf("queue has reviews %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,349 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: comment.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const deleteComment = `-- name: DeleteComment :exec
+DELETE FROM review_comment WHERE "id" = $1
+`
+
+func (q *Queries) DeleteComment(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteComment, id)
+	return err
+}
+
+const getComment = `-- name: GetComment :one
+SELECT id, review_id, parent_id, user_id, content, status, moderation_reason, moderator_id, moderated_at, created_at, updated_at FROM review_comment
+WHERE "id" = $1
+`
+
+func (q *Queries) GetComment(ctx context.Context, id int64) (ReviewComment, error) {
+	row := q.db.QueryRowContext(ctx, getComment, id)
+	var i ReviewComment
+	err := row.Scan(
+		&i.ID,
+		&i.ReviewID,
+		&i.ParentID,
+		&i.UserID,
+		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCommentForUpdate = `-- name: GetCommentForUpdate :one
+SELECT id, review_id, parent_id, user_id, content, status, moderation_reason, moderator_id, moderated_at, created_at, updated_at FROM review_comment
+WHERE "id" = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetCommentForUpdate(ctx context.Context, id int64) (ReviewComment, error) {
+	row := q.db.QueryRowContext(ctx, getCommentForUpdate, id)
+	var i ReviewComment
+	err := row.Scan(
+		&i.ID,
+		&i.ReviewID,
+		&i.ParentID,
+		&i.UserID,
+		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertComment = `-- name: InsertComment :one
+INSERT INTO
+    review_comment (
+        "review_id",
+        "parent_id",
+        "user_id",
+        "content",
+        "status",
+        "moderation_reason"
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, review_id, parent_id, user_id, content, status, moderation_reason, moderator_id, moderated_at, created_at, updated_at
+`
+
+type InsertCommentParams struct {
+	ReviewID         int64
+	ParentID         sql.NullInt64
+	UserID           int64
+	Content          string
+	Status           ReviewStatus
+	ModerationReason string
+}
+
+func (q *Queries) InsertComment(ctx context.Context, arg InsertCommentParams) (ReviewComment, error) {
+	row := q.db.QueryRowContext(ctx, insertComment,
+		arg.ReviewID,
+		arg.ParentID,
+		arg.UserID,
+		arg.Content,
+		arg.Status,
+		arg.ModerationReason,
+	)
+	var i ReviewComment
+	err := row.Scan(
+		&i.ID,
+		&i.ReviewID,
+		&i.ParentID,
+		&i.UserID,
+		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCommentReplies = `-- name: ListCommentReplies :many
+SELECT id, review_id, parent_id, user_id, content, status, moderation_reason, moderator_id, moderated_at, created_at, updated_at FROM review_comment
+WHERE
+    "parent_id" = ANY($1::int8[])
+    AND "status" = 'approved'
+ORDER BY "parent_id", "id"
+`
+
+func (q *Queries) ListCommentReplies(ctx context.Context, parentIds []int64) ([]ReviewComment, error) {
+	rows, err := q.db.QueryContext(ctx, listCommentReplies, pq.Array(parentIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewComment
+	for rows.Next() {
+		var i ReviewComment
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReviewID,
+			&i.ParentID,
+			&i.UserID,
+			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentsByStatus = `-- name: ListCommentsByStatus :many
+SELECT id, review_id, parent_id, user_id, content, status, moderation_reason, moderator_id, moderated_at, created_at, updated_at FROM review_comment
+WHERE "status" = $1 AND "id" > $3
+ORDER BY "id"
+LIMIT $2
+`
+
+type ListCommentsByStatusParams struct {
+	Status  ReviewStatus
+	Limit   int32
+	AfterID int64
+}
+
+func (q *Queries) ListCommentsByStatus(ctx context.Context, arg ListCommentsByStatusParams) ([]ReviewComment, error) {
+	rows, err := q.db.QueryContext(ctx, listCommentsByStatus, arg.Status, arg.Limit, arg.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewComment
+	for rows.Next() {
+		var i ReviewComment
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReviewID,
+			&i.ParentID,
+			&i.UserID,
+			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopLevelComments = `-- name: ListTopLevelComments :many
+SELECT id, review_id, parent_id, user_id, content, status, moderation_reason, moderator_id, moderated_at, created_at, updated_at FROM review_comment
+WHERE
+    "review_id" = $1
+    AND "parent_id" IS NULL
+    AND "status" = 'approved'
+    AND "id" > $3
+ORDER BY "id"
+LIMIT $2
+`
+
+type ListTopLevelCommentsParams struct {
+	ReviewID int64
+	Limit    int32
+	AfterID  int64
+}
+
+func (q *Queries) ListTopLevelComments(ctx context.Context, arg ListTopLevelCommentsParams) ([]ReviewComment, error) {
+	rows, err := q.db.QueryContext(ctx, listTopLevelComments, arg.ReviewID, arg.Limit, arg.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewComment
+	for rows.Next() {
+		var i ReviewComment
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReviewID,
+			&i.ParentID,
+			&i.UserID,
+			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshCommentCount = `-- name: RefreshCommentCount :exec
+UPDATE review
+SET "comment_count" = (
+        SELECT count(*)
+        FROM review_comment
+        WHERE
+            review_comment."review_id" = review."id"
+            AND review_comment."status" = 'approved'
+    )
+WHERE review."id" = $1
+`
+
+func (q *Queries) RefreshCommentCount(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, refreshCommentCount, id)
+	return err
+}
+
+const updateCommentContent = `-- name: UpdateCommentContent :one
+UPDATE review_comment
+SET
+    "content" = $2,
+    "status" = $3,
+    "moderation_reason" = $4,
+    "updated_at" = now()
+WHERE "id" = $1
+RETURNING id, review_id, parent_id, user_id, content, status, moderation_reason, moderator_id, moderated_at, created_at, updated_at
+`
+
+type UpdateCommentContentParams struct {
+	ID               int64
+	Content          string
+	Status           ReviewStatus
+	ModerationReason string
+}
+
+func (q *Queries) UpdateCommentContent(ctx context.Context, arg UpdateCommentContentParams) (ReviewComment, error) {
+	row := q.db.QueryRowContext(ctx, updateCommentContent,
+		arg.ID,
+		arg.Content,
+		arg.Status,
+		arg.ModerationReason,
+	)
+	var i ReviewComment
+	err := row.Scan(
+		&i.ID,
+		&i.ReviewID,
+		&i.ParentID,
+		&i.UserID,
+		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateCommentStatus = `-- name: UpdateCommentStatus :exec
+UPDATE review_comment
+SET
+    "status" = $2,
+    "moderation_reason" = $3,
+    "moderator_id" = $4,
+    "moderated_at" = now()
+WHERE "id" = $1
+`
+
+type UpdateCommentStatusParams struct {
+	ID               int64
+	Status           ReviewStatus
+	ModerationReason string
+	ModeratorID      sql.NullInt64
+}
+
+func (q *Queries) UpdateCommentStatus(ctx context.Context, arg UpdateCommentStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateCommentStatus,
+		arg.ID,
+		arg.Status,
+		arg.ModerationReason,
+		arg.ModeratorID,
+	)
+	return err
+}
@@ -0,0 +1,137 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: idempotency.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :execrows
+
+INSERT INTO
+    idempotency_key (
+        "user_id",
+        "method",
+        "key",
+        "fingerprint",
+        "expires_at"
+    )
+VALUES ($1, $2, $3, $4, $5) ON CONFLICT ("user_id", "method", "key") DO
+UPDATE
+SET
+    "fingerprint" = EXCLUDED."fingerprint",
+    "response" = NULL,
+    "created_at" = now(),
+    "expires_at" = EXCLUDED."expires_at"
+WHERE
+    idempotency_key."expires_at" < now()
+    OR (
+        idempotency_key."response" IS NULL
+        AND idempotency_key."created_at" < $6
+    )
+`
+
+type ClaimIdempotencyKeyParams struct {
+	UserID      int64
+	Method      string
+	Key         string
+	Fingerprint []byte
+	ExpiresAt   time.Time
+	StaleBefore time.Time
+}
+
+// takes over expired keys and keys whose first request never completed
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimIdempotencyKey,
+		arg.UserID,
+		arg.Method,
+		arg.Key,
+		arg.Fingerprint,
+		arg.ExpiresAt,
+		arg.StaleBefore,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_key WHERE "expires_at" < now()
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT user_id, method, key, fingerprint, response, created_at, expires_at FROM idempotency_key
+WHERE "user_id" = $1 AND "method" = $2 AND "key" = $3
+`
+
+type GetIdempotencyKeyParams struct {
+	UserID int64
+	Method string
+	Key    string
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, arg.UserID, arg.Method, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.UserID,
+		&i.Method,
+		&i.Key,
+		&i.Fingerprint,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_key
+WHERE "user_id" = $1 AND "method" = $2 AND "key" = $3 AND "response" IS NULL
+`
+
+type ReleaseIdempotencyKeyParams struct {
+	UserID int64
+	Method string
+	Key    string
+}
+
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, releaseIdempotencyKey, arg.UserID, arg.Method, arg.Key)
+	return err
+}
+
+const saveIdempotentResponse = `-- name: SaveIdempotentResponse :exec
+UPDATE idempotency_key SET "response" = $4
+WHERE "user_id" = $1 AND "method" = $2 AND "key" = $3
+`
+
+type SaveIdempotentResponseParams struct {
+	UserID   int64
+	Method   string
+	Key      string
+	Response []byte
+}
+
+func (q *Queries) SaveIdempotentResponse(ctx context.Context, arg SaveIdempotentResponseParams) error {
+	_, err := q.db.ExecContext(ctx, saveIdempotentResponse,
+		arg.UserID,
+		arg.Method,
+		arg.Key,
+		arg.Response,
+	)
+	return err
+}
@@ -4,7 +4,73 @@
 
 package repository
 
-import ()
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+	ReviewStatusHidden   ReviewStatus = "hidden"
+)
+
+func (e *ReviewStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReviewStatus(s)
+	case string:
+		*e = ReviewStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReviewStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReviewStatus struct {
+	ReviewStatus ReviewStatus
+	Valid        bool // Valid is true if ReviewStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReviewStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReviewStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReviewStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReviewStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReviewStatus), nil
+}
+
+type AnswerUpvote struct {
+	AnswerID  int64
+	UserID    int64
+	CreatedAt time.Time
+}
+
+type IdempotencyKey struct {
+	UserID      int64
+	Method      string
+	Key         string
+	Fingerprint []byte
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
 
 type Image struct {
 	ID       int64
@@ -12,10 +78,118 @@ type Image struct {
 	ImageUrl string
 }
 
+type OutboxEvent struct {
+	ID          int64
+	AggregateID int64
+	EventType   string
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+	PublishedAt sql.NullTime
+}
+
+type ProductQuestion struct {
+	ID          int64
+	ProductID   int64
+	UserID      int64
+	Content     string
+	AnswerCount int32
+	CreatedAt   time.Time
+}
+
+type QuestionAnswer struct {
+	ID            int64
+	QuestionID    int64
+	UserID        int64
+	Content       string
+	VerifiedBuyer bool
+	FromSupplier  bool
+	UpvoteCount   int32
+	CreatedAt     time.Time
+}
+
 type Review struct {
+	ID               int64
+	UserID           int64
+	ProductID        int64
+	NumStar          int32
+	Content          string
+	Status           ReviewStatus
+	ModerationReason string
+	ModeratorID      sql.NullInt64
+	ModeratedAt      sql.NullTime
+	CreatedAt        time.Time
+	HelpfulCount     int32
+	NotHelpfulCount  int32
+	CommentCount     int32
+	SearchVector     interface{}
+	CategoryID       int64
+}
+
+type ReviewComment struct {
+	ID               int64
+	ReviewID         int64
+	ParentID         sql.NullInt64
+	UserID           int64
+	Content          string
+	Status           ReviewStatus
+	ModerationReason string
+	ModeratorID      sql.NullInt64
+	ModeratedAt      sql.NullTime
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type ReviewModerationEvent struct {
+	ID          int64
+	ReviewID    int64
+	FromStatus  ReviewStatus
+	ToStatus    ReviewStatus
+	Reason      string
+	Note        string
+	ModeratorID sql.NullInt64
+	CreatedAt   time.Time
+}
+
+type ReviewReply struct {
+	ReviewID   int64
+	SupplierID int64
+	Content    string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type ReviewReport struct {
 	ID        int64
+	ReviewID  int64
+	UserID    int64
+	Reason    string
+	Comment   string
+	CreatedAt time.Time
+}
+
+type ReviewSubRating struct {
+	ReviewID  int64
+	Dimension string
+	Rating    int32
+}
+
+type ReviewTag struct {
+	ReviewID int64
+	TagID    int64
+}
+
+type ReviewVote struct {
+	ReviewID  int64
 	UserID    int64
-	ProductID int64
-	NumStar   int32
-	Content   string
+	Helpful   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Tag struct {
+	ID        int64
+	Name      string
+	Sentiment string
+	Active    bool
+	CreatedAt time.Time
 }
@@ -0,0 +1,195 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: moderation.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+const getReviewForUpdate = `-- name: GetReviewForUpdate :one
+SELECT id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id FROM review
+WHERE "id" = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetReviewForUpdate(ctx context.Context, id int64) (Review, error) {
+	row := q.db.QueryRowContext(ctx, getReviewForUpdate, id)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProductID,
+		&i.NumStar,
+		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.HelpfulCount,
+		&i.NotHelpfulCount,
+		&i.CommentCount,
+		&i.SearchVector,
+		&i.CategoryID,
+	)
+	return i, err
+}
+
+const insertModerationEvent = `-- name: InsertModerationEvent :exec
+INSERT INTO
+    review_moderation_event (
+        "review_id",
+        "from_status",
+        "to_status",
+        "reason",
+        "note",
+        "moderator_id"
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type InsertModerationEventParams struct {
+	ReviewID    int64
+	FromStatus  ReviewStatus
+	ToStatus    ReviewStatus
+	Reason      string
+	Note        string
+	ModeratorID sql.NullInt64
+}
+
+func (q *Queries) InsertModerationEvent(ctx context.Context, arg InsertModerationEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertModerationEvent,
+		arg.ReviewID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Reason,
+		arg.Note,
+		arg.ModeratorID,
+	)
+	return err
+}
+
+const listModerationEvents = `-- name: ListModerationEvents :many
+SELECT id, review_id, from_status, to_status, reason, note, moderator_id, created_at FROM review_moderation_event
+WHERE "review_id" = $1
+ORDER BY "id"
+`
+
+func (q *Queries) ListModerationEvents(ctx context.Context, reviewID int64) ([]ReviewModerationEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listModerationEvents, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewModerationEvent
+	for rows.Next() {
+		var i ReviewModerationEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReviewID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Reason,
+			&i.Note,
+			&i.ModeratorID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReviewsAwaitingModeration = `-- name: ListReviewsAwaitingModeration :many
+SELECT id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id FROM review
+WHERE (
+        "status" = 'pending'
+        OR ("status" = 'hidden' AND "moderator_id" IS NULL)
+    )
+    AND "id" > $2
+ORDER BY "id"
+LIMIT $1
+`
+
+type ListReviewsAwaitingModerationParams struct {
+	Limit   int32
+	AfterID int64
+}
+
+// pending reviews, and reviews hidden by reports which no moderator decided on
+func (q *Queries) ListReviewsAwaitingModeration(ctx context.Context, arg ListReviewsAwaitingModerationParams) ([]Review, error) {
+	rows, err := q.db.QueryContext(ctx, listReviewsAwaitingModeration, arg.Limit, arg.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Review
+	for rows.Next() {
+		var i Review
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProductID,
+			&i.NumStar,
+			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.HelpfulCount,
+			&i.NotHelpfulCount,
+			&i.CommentCount,
+			&i.SearchVector,
+			&i.CategoryID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateReviewStatus = `-- name: UpdateReviewStatus :exec
+UPDATE review
+SET
+    "status" = $2,
+    "moderation_reason" = $3,
+    "moderator_id" = $4,
+    "moderated_at" = now()
+WHERE "id" = $1
+`
+
+type UpdateReviewStatusParams struct {
+	ID               int64
+	Status           ReviewStatus
+	ModerationReason string
+	ModeratorID      sql.NullInt64
+}
+
+func (q *Queries) UpdateReviewStatus(ctx context.Context, arg UpdateReviewStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateReviewStatus,
+		arg.ID,
+		arg.Status,
+		arg.ModerationReason,
+		arg.ModeratorID,
+	)
+	return err
+}
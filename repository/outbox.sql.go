@@ -0,0 +1,108 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: outbox.sql
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+SELECT id, aggregate_id, event_type, payload, created_at, published_at FROM outbox_event
+WHERE "published_at" IS NULL
+ORDER BY "id"
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+// relays running in other replicas skip the events locked here
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, limit int32) ([]OutboxEvent, error) {
+	rows, err := q.db.QueryContext(ctx, claimOutboxEvents, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxEvent
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePublishedOutboxEvents = `-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox_event
+WHERE "published_at" < $1::timestamptz
+`
+
+func (q *Queries) DeletePublishedOutboxEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePublishedOutboxEvents, publishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertOutboxEvent = `-- name: InsertOutboxEvent :exec
+INSERT INTO
+    outbox_event (
+        "aggregate_id",
+        "event_type",
+        "payload"
+    )
+VALUES ($1, $2, $3)
+`
+
+type InsertOutboxEventParams struct {
+	AggregateID int64
+	EventType   string
+	Payload     json.RawMessage
+}
+
+func (q *Queries) InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertOutboxEvent, arg.AggregateID, arg.EventType, arg.Payload)
+	return err
+}
+
+const markOutboxEventsPublished = `-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox_event
+SET "published_at" = now()
+WHERE "id" = ANY($1::int8[])
+`
+
+func (q *Queries) MarkOutboxEventsPublished(ctx context.Context, ids []int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventsPublished, pq.Array(ids))
+	return err
+}
+
+const notifyReviewEvent = `-- name: NotifyReviewEvent :exec
+SELECT pg_notify('review_events', $1::text)
+`
+
+// delivered to listeners of every replica once the transaction commits
+func (q *Queries) NotifyReviewEvent(ctx context.Context, payload string) error {
+	_, err := q.db.ExecContext(ctx, notifyReviewEvent, payload)
+	return err
+}
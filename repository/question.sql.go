@@ -0,0 +1,256 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: question.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const getAnswer = `-- name: GetAnswer :one
+SELECT id, question_id, user_id, content, verified_buyer, from_supplier, upvote_count, created_at FROM question_answer
+WHERE "id" = $1
+`
+
+func (q *Queries) GetAnswer(ctx context.Context, id int64) (QuestionAnswer, error) {
+	row := q.db.QueryRowContext(ctx, getAnswer, id)
+	var i QuestionAnswer
+	err := row.Scan(
+		&i.ID,
+		&i.QuestionID,
+		&i.UserID,
+		&i.Content,
+		&i.VerifiedBuyer,
+		&i.FromSupplier,
+		&i.UpvoteCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getQuestion = `-- name: GetQuestion :one
+SELECT id, product_id, user_id, content, answer_count, created_at FROM product_question
+WHERE "id" = $1
+`
+
+func (q *Queries) GetQuestion(ctx context.Context, id int64) (ProductQuestion, error) {
+	row := q.db.QueryRowContext(ctx, getQuestion, id)
+	var i ProductQuestion
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Content,
+		&i.AnswerCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const incrementAnswerCount = `-- name: IncrementAnswerCount :exec
+UPDATE product_question
+SET "answer_count" = "answer_count" + 1
+WHERE "id" = $1
+`
+
+func (q *Queries) IncrementAnswerCount(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, incrementAnswerCount, id)
+	return err
+}
+
+const incrementUpvoteCount = `-- name: IncrementUpvoteCount :one
+UPDATE question_answer
+SET "upvote_count" = "upvote_count" + 1
+WHERE "id" = $1
+RETURNING "upvote_count"
+`
+
+func (q *Queries) IncrementUpvoteCount(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementUpvoteCount, id)
+	var upvote_count int32
+	err := row.Scan(&upvote_count)
+	return upvote_count, err
+}
+
+const insertAnswer = `-- name: InsertAnswer :one
+INSERT INTO
+    question_answer (
+        "question_id",
+        "user_id",
+        "content",
+        "verified_buyer",
+        "from_supplier"
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, question_id, user_id, content, verified_buyer, from_supplier, upvote_count, created_at
+`
+
+type InsertAnswerParams struct {
+	QuestionID    int64
+	UserID        int64
+	Content       string
+	VerifiedBuyer bool
+	FromSupplier  bool
+}
+
+func (q *Queries) InsertAnswer(ctx context.Context, arg InsertAnswerParams) (QuestionAnswer, error) {
+	row := q.db.QueryRowContext(ctx, insertAnswer,
+		arg.QuestionID,
+		arg.UserID,
+		arg.Content,
+		arg.VerifiedBuyer,
+		arg.FromSupplier,
+	)
+	var i QuestionAnswer
+	err := row.Scan(
+		&i.ID,
+		&i.QuestionID,
+		&i.UserID,
+		&i.Content,
+		&i.VerifiedBuyer,
+		&i.FromSupplier,
+		&i.UpvoteCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertAnswerUpvote = `-- name: InsertAnswerUpvote :execrows
+INSERT INTO
+    answer_upvote ("answer_id", "user_id")
+VALUES ($1, $2)
+ON CONFLICT ("answer_id", "user_id") DO NOTHING
+`
+
+type InsertAnswerUpvoteParams struct {
+	AnswerID int64
+	UserID   int64
+}
+
+func (q *Queries) InsertAnswerUpvote(ctx context.Context, arg InsertAnswerUpvoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertAnswerUpvote, arg.AnswerID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertQuestion = `-- name: InsertQuestion :one
+INSERT INTO
+    product_question (
+        "product_id",
+        "user_id",
+        "content"
+    )
+VALUES ($1, $2, $3)
+RETURNING id, product_id, user_id, content, answer_count, created_at
+`
+
+type InsertQuestionParams struct {
+	ProductID int64
+	UserID    int64
+	Content   string
+}
+
+func (q *Queries) InsertQuestion(ctx context.Context, arg InsertQuestionParams) (ProductQuestion, error) {
+	row := q.db.QueryRowContext(ctx, insertQuestion, arg.ProductID, arg.UserID, arg.Content)
+	var i ProductQuestion
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.UserID,
+		&i.Content,
+		&i.AnswerCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAnswersByQuestionIDs = `-- name: ListAnswersByQuestionIDs :many
+SELECT id, question_id, user_id, content, verified_buyer, from_supplier, upvote_count, created_at FROM question_answer
+WHERE "question_id" = ANY($1::int8[])
+ORDER BY
+    "question_id",
+    "from_supplier" DESC,
+    "upvote_count" DESC,
+    "id"
+`
+
+func (q *Queries) ListAnswersByQuestionIDs(ctx context.Context, questionIds []int64) ([]QuestionAnswer, error) {
+	rows, err := q.db.QueryContext(ctx, listAnswersByQuestionIDs, pq.Array(questionIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QuestionAnswer
+	for rows.Next() {
+		var i QuestionAnswer
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuestionID,
+			&i.UserID,
+			&i.Content,
+			&i.VerifiedBuyer,
+			&i.FromSupplier,
+			&i.UpvoteCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuestionsByProductID = `-- name: ListQuestionsByProductID :many
+SELECT id, product_id, user_id, content, answer_count, created_at FROM product_question
+WHERE "product_id" = $1 AND "id" > $3
+ORDER BY "id"
+LIMIT $2
+`
+
+type ListQuestionsByProductIDParams struct {
+	ProductID int64
+	Limit     int32
+	AfterID   int64
+}
+
+func (q *Queries) ListQuestionsByProductID(ctx context.Context, arg ListQuestionsByProductIDParams) ([]ProductQuestion, error) {
+	rows, err := q.db.QueryContext(ctx, listQuestionsByProductID, arg.ProductID, arg.Limit, arg.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductQuestion
+	for rows.Next() {
+		var i ProductQuestion
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.UserID,
+			&i.Content,
+			&i.AnswerCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,125 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: rating.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const getProductRating = `-- name: GetProductRating :one
+SELECT
+    count(*) AS "review_count",
+    coalesce(avg("num_star"), 0)::float8 AS "average_star"
+FROM review
+WHERE "product_id" = $1 AND "status" = 'approved'
+`
+
+type GetProductRatingRow struct {
+	ReviewCount int64
+	AverageStar float64
+}
+
+func (q *Queries) GetProductRating(ctx context.Context, productID int64) (GetProductRatingRow, error) {
+	row := q.db.QueryRowContext(ctx, getProductRating, productID)
+	var i GetProductRatingRow
+	err := row.Scan(&i.ReviewCount, &i.AverageStar)
+	return i, err
+}
+
+const getProductSubRatings = `-- name: GetProductSubRatings :many
+SELECT
+    review_sub_rating."dimension",
+    count(*) AS "rating_count",
+    avg(review_sub_rating."rating")::float8 AS "average_rating"
+FROM review_sub_rating
+    INNER JOIN review ON review."id" = review_sub_rating."review_id"
+WHERE
+    review."product_id" = $1
+    AND review."status" = 'approved'
+GROUP BY review_sub_rating."dimension"
+ORDER BY review_sub_rating."dimension"
+`
+
+type GetProductSubRatingsRow struct {
+	Dimension     string
+	RatingCount   int64
+	AverageRating float64
+}
+
+func (q *Queries) GetProductSubRatings(ctx context.Context, productID int64) ([]GetProductSubRatingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProductSubRatings, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductSubRatingsRow
+	for rows.Next() {
+		var i GetProductSubRatingsRow
+		if err := rows.Scan(&i.Dimension, &i.RatingCount, &i.AverageRating); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubRatingsByReviewIDs = `-- name: GetSubRatingsByReviewIDs :many
+SELECT review_id, dimension, rating FROM review_sub_rating
+WHERE "review_id" = ANY($1::int8[])
+ORDER BY "review_id", "dimension"
+`
+
+func (q *Queries) GetSubRatingsByReviewIDs(ctx context.Context, reviewIds []int64) ([]ReviewSubRating, error) {
+	rows, err := q.db.QueryContext(ctx, getSubRatingsByReviewIDs, pq.Array(reviewIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewSubRating
+	for rows.Next() {
+		var i ReviewSubRating
+		if err := rows.Scan(&i.ReviewID, &i.Dimension, &i.Rating); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSubRating = `-- name: InsertSubRating :exec
+INSERT INTO
+    review_sub_rating (
+        "review_id",
+        "dimension",
+        "rating"
+    )
+VALUES ($1, $2, $3)
+`
+
+type InsertSubRatingParams struct {
+	ReviewID  int64
+	Dimension string
+	Rating    int32
+}
+
+func (q *Queries) InsertSubRating(ctx context.Context, arg InsertSubRatingParams) error {
+	_, err := q.db.ExecContext(ctx, insertSubRating, arg.ReviewID, arg.Dimension, arg.Rating)
+	return err
+}
@@ -0,0 +1,83 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: reply.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const getRepliesByReviewIDs = `-- name: GetRepliesByReviewIDs :many
+SELECT review_id, supplier_id, content, created_at, updated_at FROM review_reply
+WHERE "review_id" = ANY($1::int8[])
+`
+
+func (q *Queries) GetRepliesByReviewIDs(ctx context.Context, reviewIds []int64) ([]ReviewReply, error) {
+	rows, err := q.db.QueryContext(ctx, getRepliesByReviewIDs, pq.Array(reviewIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewReply
+	for rows.Next() {
+		var i ReviewReply
+		if err := rows.Scan(
+			&i.ReviewID,
+			&i.SupplierID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertReviewReply = `-- name: UpsertReviewReply :one
+INSERT INTO
+    review_reply (
+        "review_id",
+        "supplier_id",
+        "content"
+    )
+VALUES ($1, $2, $3)
+ON CONFLICT ("review_id") DO
+UPDATE
+SET
+    "content" = excluded."content",
+    "updated_at" = now()
+WHERE
+    review_reply."supplier_id" = excluded."supplier_id"
+RETURNING review_id, supplier_id, content, created_at, updated_at
+`
+
+type UpsertReviewReplyParams struct {
+	ReviewID   int64
+	SupplierID int64
+	Content    string
+}
+
+func (q *Queries) UpsertReviewReply(ctx context.Context, arg UpsertReviewReplyParams) (ReviewReply, error) {
+	row := q.db.QueryRowContext(ctx, upsertReviewReply, arg.ReviewID, arg.SupplierID, arg.Content)
+	var i ReviewReply
+	err := row.Scan(
+		&i.ReviewID,
+		&i.SupplierID,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
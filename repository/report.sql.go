@@ -0,0 +1,144 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: report.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const countReportsSince = `-- name: CountReportsSince :one
+SELECT count(*) FROM review_report
+WHERE "review_id" = $1 AND "created_at" > $2
+`
+
+type CountReportsSinceParams struct {
+	ReviewID int64
+	Since    time.Time
+}
+
+func (q *Queries) CountReportsSince(ctx context.Context, arg CountReportsSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countReportsSince, arg.ReviewID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const insertReviewReport = `-- name: InsertReviewReport :execrows
+INSERT INTO
+    review_report (
+        "review_id",
+        "user_id",
+        "reason",
+        "comment"
+    )
+VALUES ($1, $2, $3, $4)
+ON CONFLICT ("review_id", "user_id") DO NOTHING
+`
+
+type InsertReviewReportParams struct {
+	ReviewID int64
+	UserID   int64
+	Reason   string
+	Comment  string
+}
+
+func (q *Queries) InsertReviewReport(ctx context.Context, arg InsertReviewReportParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertReviewReport,
+		arg.ReviewID,
+		arg.UserID,
+		arg.Reason,
+		arg.Comment,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listReportedReviews = `-- name: ListReportedReviews :many
+SELECT
+    "review_id",
+    count(*) AS "report_count",
+    max("created_at")::timestamptz AS "last_reported_at"
+FROM review_report
+WHERE "review_id" > $2
+GROUP BY "review_id"
+ORDER BY "review_id"
+LIMIT $1
+`
+
+type ListReportedReviewsParams struct {
+	Limit   int32
+	AfterID int64
+}
+
+type ListReportedReviewsRow struct {
+	ReviewID       int64
+	ReportCount    int64
+	LastReportedAt time.Time
+}
+
+func (q *Queries) ListReportedReviews(ctx context.Context, arg ListReportedReviewsParams) ([]ListReportedReviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listReportedReviews, arg.Limit, arg.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListReportedReviewsRow
+	for rows.Next() {
+		var i ListReportedReviewsRow
+		if err := rows.Scan(&i.ReviewID, &i.ReportCount, &i.LastReportedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReportsByReviewIDs = `-- name: ListReportsByReviewIDs :many
+SELECT id, review_id, user_id, reason, comment, created_at FROM review_report
+WHERE "review_id" = ANY($1::int8[])
+ORDER BY "review_id", "id"
+`
+
+func (q *Queries) ListReportsByReviewIDs(ctx context.Context, reviewIds []int64) ([]ReviewReport, error) {
+	rows, err := q.db.QueryContext(ctx, listReportsByReviewIDs, pq.Array(reviewIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewReport
+	for rows.Next() {
+		var i ReviewReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReviewID,
+			&i.UserID,
+			&i.Reason,
+			&i.Comment,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,6 +7,8 @@ package repository
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const deleteReview = `-- name: DeleteReview :exec
@@ -20,12 +22,31 @@ func (q *Queries) DeleteReview(ctx context.Context, id int64) error {
 }
 
 const getAllReviewByProductID = `-- name: GetAllReviewByProductID :many
-SELECT id, user_id, product_id, num_star, content FROM review
-WHERE "product_id" = $1
+SELECT id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id FROM review
+WHERE
+    "product_id" = $1
+    AND "status" = 'approved'
+    AND (
+        cardinality($2::int8[]) = 0
+        OR "id" IN (
+            SELECT "review_id"
+            FROM review_tag
+            WHERE "tag_id" = ANY($2::int8[])
+            GROUP BY "review_id"
+            HAVING count(*) = cardinality($2::int8[])
+        )
+    )
+ORDER BY "id" DESC
 `
 
-func (q *Queries) GetAllReviewByProductID(ctx context.Context, productID int64) ([]Review, error) {
-	rows, err := q.db.QueryContext(ctx, getAllReviewByProductID, productID)
+type GetAllReviewByProductIDParams struct {
+	ProductID int64
+	TagIds    []int64
+}
+
+// reviews must carry every tag in tag_ids, which may be empty
+func (q *Queries) GetAllReviewByProductID(ctx context.Context, arg GetAllReviewByProductIDParams) ([]Review, error) {
+	rows, err := q.db.QueryContext(ctx, getAllReviewByProductID, arg.ProductID, pq.Array(arg.TagIds))
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +60,16 @@ func (q *Queries) GetAllReviewByProductID(ctx context.Context, productID int64)
 			&i.ProductID,
 			&i.NumStar,
 			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.HelpfulCount,
+			&i.NotHelpfulCount,
+			&i.CommentCount,
+			&i.SearchVector,
+			&i.CategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -81,6 +112,176 @@ func (q *Queries) GetImagesByOrderID(ctx context.Context, reviewID int64) ([]str
 	return items, nil
 }
 
+const getMostHelpfulReviewsByProductID = `-- name: GetMostHelpfulReviewsByProductID :many
+SELECT id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id FROM review
+WHERE
+    "product_id" = $1
+    AND "status" = 'approved'
+    AND (
+        cardinality($2::int8[]) = 0
+        OR "id" IN (
+            SELECT "review_id"
+            FROM review_tag
+            WHERE "tag_id" = ANY($2::int8[])
+            GROUP BY "review_id"
+            HAVING count(*) = cardinality($2::int8[])
+        )
+    )
+ORDER BY "helpful_count" DESC, "id" DESC
+`
+
+type GetMostHelpfulReviewsByProductIDParams struct {
+	ProductID int64
+	TagIds    []int64
+}
+
+func (q *Queries) GetMostHelpfulReviewsByProductID(ctx context.Context, arg GetMostHelpfulReviewsByProductIDParams) ([]Review, error) {
+	rows, err := q.db.QueryContext(ctx, getMostHelpfulReviewsByProductID, arg.ProductID, pq.Array(arg.TagIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Review
+	for rows.Next() {
+		var i Review
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProductID,
+			&i.NumStar,
+			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.HelpfulCount,
+			&i.NotHelpfulCount,
+			&i.CommentCount,
+			&i.SearchVector,
+			&i.CategoryID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReviewByID = `-- name: GetReviewByID :one
+SELECT id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id FROM review
+WHERE "id" = $1
+`
+
+func (q *Queries) GetReviewByID(ctx context.Context, id int64) (Review, error) {
+	row := q.db.QueryRowContext(ctx, getReviewByID, id)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProductID,
+		&i.NumStar,
+		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.HelpfulCount,
+		&i.NotHelpfulCount,
+		&i.CommentCount,
+		&i.SearchVector,
+		&i.CategoryID,
+	)
+	return i, err
+}
+
+const getReviewedProductIDs = `-- name: GetReviewedProductIDs :many
+SELECT DISTINCT "product_id" FROM review
+WHERE
+    "user_id" = $1
+    AND "product_id" = ANY($2::int8[])
+`
+
+type GetReviewedProductIDsParams struct {
+	UserID     int64
+	ProductIds []int64
+}
+
+func (q *Queries) GetReviewedProductIDs(ctx context.Context, arg GetReviewedProductIDsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getReviewedProductIDs, arg.UserID, pq.Array(arg.ProductIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var product_id int64
+		if err := rows.Scan(&product_id); err != nil {
+			return nil, err
+		}
+		items = append(items, product_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReviewsByIDs = `-- name: GetReviewsByIDs :many
+SELECT id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id FROM review
+WHERE "id" = ANY($1::int8[])
+ORDER BY "id"
+`
+
+func (q *Queries) GetReviewsByIDs(ctx context.Context, ids []int64) ([]Review, error) {
+	rows, err := q.db.QueryContext(ctx, getReviewsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Review
+	for rows.Next() {
+		var i Review
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProductID,
+			&i.NumStar,
+			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.HelpfulCount,
+			&i.NotHelpfulCount,
+			&i.CommentCount,
+			&i.SearchVector,
+			&i.CategoryID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertImage = `-- name: InsertImage :exec
 
 INSERT INTO image ("review_id", "image_url") VALUES ($1, $2)
@@ -103,16 +304,22 @@ INSERT INTO
         "user_id",
         "product_id",
         "num_star",
-        "content"
+        "content",
+        "status",
+        "moderation_reason",
+        "category_id"
     )
-VALUES ($1, $2, $3, $4) RETURNING  id, user_id, product_id, num_star, content
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING  id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id
 `
 
 type InsertReviewParams struct {
-	UserID    int64
-	ProductID int64
-	NumStar   int32
-	Content   string
+	UserID           int64
+	ProductID        int64
+	NumStar          int32
+	Content          string
+	Status           ReviewStatus
+	ModerationReason string
+	CategoryID       int64
 }
 
 func (q *Queries) InsertReview(ctx context.Context, arg InsertReviewParams) (Review, error) {
@@ -121,6 +328,9 @@ func (q *Queries) InsertReview(ctx context.Context, arg InsertReviewParams) (Rev
 		arg.ProductID,
 		arg.NumStar,
 		arg.Content,
+		arg.Status,
+		arg.ModerationReason,
+		arg.CategoryID,
 	)
 	var i Review
 	err := row.Scan(
@@ -129,49 +339,64 @@ func (q *Queries) InsertReview(ctx context.Context, arg InsertReviewParams) (Rev
 		&i.ProductID,
 		&i.NumStar,
 		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.HelpfulCount,
+		&i.NotHelpfulCount,
+		&i.CommentCount,
+		&i.SearchVector,
+		&i.CategoryID,
 	)
 	return i, err
 }
 
-const selectReviewByProductID = `-- name: SelectReviewByProductID :many
-
-SELECT
-    review.id,
-    "user_id",
-    "product_id",
-    "num_star",
-    "image_url",
-    "content"
-FROM review
-    INNER JOIN image ON review.id = image.review_id
-WHERE review.id = $1
+const listReviewsByUser = `-- name: ListReviewsByUser :many
+SELECT id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id FROM review
+WHERE
+    "user_id" = $1
+    AND (
+        $3::int8 = 0
+        OR "id" < $3
+    )
+ORDER BY "id" DESC
+LIMIT $2
 `
 
-type SelectReviewByProductIDRow struct {
-	ID        int64
-	UserID    int64
-	ProductID int64
-	NumStar   int32
-	ImageUrl  string
-	Content   string
+type ListReviewsByUserParams struct {
+	UserID   int64
+	Limit    int32
+	BeforeID int64
 }
 
-func (q *Queries) SelectReviewByProductID(ctx context.Context, id int64) ([]SelectReviewByProductIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, selectReviewByProductID, id)
+// newest first, resuming before before_id unless it is 0
+func (q *Queries) ListReviewsByUser(ctx context.Context, arg ListReviewsByUserParams) ([]Review, error) {
+	rows, err := q.db.QueryContext(ctx, listReviewsByUser, arg.UserID, arg.Limit, arg.BeforeID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []SelectReviewByProductIDRow
+	var items []Review
 	for rows.Next() {
-		var i SelectReviewByProductIDRow
+		var i Review
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
 			&i.ProductID,
 			&i.NumStar,
-			&i.ImageUrl,
 			&i.Content,
+			&i.Status,
+			&i.ModerationReason,
+			&i.ModeratorID,
+			&i.ModeratedAt,
+			&i.CreatedAt,
+			&i.HelpfulCount,
+			&i.NotHelpfulCount,
+			&i.CommentCount,
+			&i.SearchVector,
+			&i.CategoryID,
 		); err != nil {
 			return nil, err
 		}
@@ -185,3 +410,37 @@ func (q *Queries) SelectReviewByProductID(ctx context.Context, id int64) ([]Sele
 	}
 	return items, nil
 }
+
+const updateReviewContent = `-- name: UpdateReviewContent :one
+UPDATE review SET "content" = $2
+WHERE "id" = $1
+RETURNING id, user_id, product_id, num_star, content, status, moderation_reason, moderator_id, moderated_at, created_at, helpful_count, not_helpful_count, comment_count, search_vector, category_id
+`
+
+type UpdateReviewContentParams struct {
+	ID      int64
+	Content string
+}
+
+func (q *Queries) UpdateReviewContent(ctx context.Context, arg UpdateReviewContentParams) (Review, error) {
+	row := q.db.QueryRowContext(ctx, updateReviewContent, arg.ID, arg.Content)
+	var i Review
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProductID,
+		&i.NumStar,
+		&i.Content,
+		&i.Status,
+		&i.ModerationReason,
+		&i.ModeratorID,
+		&i.ModeratedAt,
+		&i.CreatedAt,
+		&i.HelpfulCount,
+		&i.NotHelpfulCount,
+		&i.CommentCount,
+		&i.SearchVector,
+		&i.CategoryID,
+	)
+	return i, err
+}
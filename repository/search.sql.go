@@ -0,0 +1,122 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: search.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const searchReviews = `-- name: SearchReviews :many
+SELECT
+    review."id",
+    review."user_id",
+    review."product_id",
+    review."num_star",
+    review."content",
+    review."status",
+    review."helpful_count",
+    review."not_helpful_count",
+    review."comment_count",
+    review."created_at",
+    ts_rank(review."search_vector", query)::float4 AS "rank",
+    -- matches are delimited with control characters, the snippet is escaped
+    -- before they are turned into markup
+    ts_headline(
+        'public.vn_unaccent',
+        review."content",
+        query,
+        'StartSel="' || chr(2) || '", StopSel="' || chr(3) || '", MaxFragments=2, MaxWords=30, MinWords=10'
+    )::text AS "snippet"
+FROM
+    review,
+    websearch_to_tsquery('public.vn_unaccent', $1) AS query
+WHERE
+    review."search_vector" @@ query
+    AND review."status" = 'approved'
+    AND (
+        $2::int8 = 0
+        OR review."product_id" = $2
+    )
+    AND review."num_star" BETWEEN $3 AND $4
+    AND (
+        $5::int8 = 0
+        OR (ts_rank(review."search_vector", query)::float4, review."id") < ($6::float4, $5::int8)
+    )
+ORDER BY "rank" DESC, review."id" DESC
+LIMIT $7
+`
+
+type SearchReviewsParams struct {
+	Query     string
+	ProductID int64
+	MinStar   int32
+	MaxStar   int32
+	AfterID   int64
+	AfterRank float32
+	PageSize  int32
+}
+
+type SearchReviewsRow struct {
+	ID              int64
+	UserID          int64
+	ProductID       int64
+	NumStar         int32
+	Content         string
+	Status          ReviewStatus
+	HelpfulCount    int32
+	NotHelpfulCount int32
+	CommentCount    int32
+	CreatedAt       time.Time
+	Rank            float32
+	Snippet         string
+}
+
+// results are ranked, then ordered by id, and resume after the
+// (after_rank, after_id) cursor unless after_id is 0
+func (q *Queries) SearchReviews(ctx context.Context, arg SearchReviewsParams) ([]SearchReviewsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchReviews,
+		arg.Query,
+		arg.ProductID,
+		arg.MinStar,
+		arg.MaxStar,
+		arg.AfterID,
+		arg.AfterRank,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchReviewsRow
+	for rows.Next() {
+		var i SearchReviewsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProductID,
+			&i.NumStar,
+			&i.Content,
+			&i.Status,
+			&i.HelpfulCount,
+			&i.NotHelpfulCount,
+			&i.CommentCount,
+			&i.CreatedAt,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,263 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: tag.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const getActiveTagsByIDs = `-- name: GetActiveTagsByIDs :many
+SELECT id, name, sentiment, active, created_at FROM tag
+WHERE "id" = ANY($1::int8[]) AND "active"
+`
+
+func (q *Queries) GetActiveTagsByIDs(ctx context.Context, ids []int64) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveTagsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Sentiment,
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductTagFrequencies = `-- name: GetProductTagFrequencies :many
+SELECT
+    tag.id, tag.name, tag.sentiment, tag.active, tag.created_at,
+    count(*) AS "review_count"
+FROM review_tag
+    INNER JOIN review ON review."id" = review_tag."review_id"
+    INNER JOIN tag ON tag."id" = review_tag."tag_id"
+WHERE
+    review."product_id" = $1
+    AND review."status" = 'approved'
+    AND tag."active"
+GROUP BY tag."id"
+ORDER BY "review_count" DESC, tag."id"
+`
+
+type GetProductTagFrequenciesRow struct {
+	ID          int64
+	Name        string
+	Sentiment   string
+	Active      bool
+	CreatedAt   time.Time
+	ReviewCount int64
+}
+
+func (q *Queries) GetProductTagFrequencies(ctx context.Context, productID int64) ([]GetProductTagFrequenciesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProductTagFrequencies, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductTagFrequenciesRow
+	for rows.Next() {
+		var i GetProductTagFrequenciesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Sentiment,
+			&i.Active,
+			&i.CreatedAt,
+			&i.ReviewCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagsByReviewIDs = `-- name: GetTagsByReviewIDs :many
+SELECT
+    review_tag."review_id",
+    tag.id, tag.name, tag.sentiment, tag.active, tag.created_at
+FROM review_tag
+    INNER JOIN tag ON tag."id" = review_tag."tag_id"
+WHERE
+    review_tag."review_id" = ANY($1::int8[])
+ORDER BY
+    review_tag."review_id",
+    tag."id"
+`
+
+type GetTagsByReviewIDsRow struct {
+	ReviewID  int64
+	ID        int64
+	Name      string
+	Sentiment string
+	Active    bool
+	CreatedAt time.Time
+}
+
+func (q *Queries) GetTagsByReviewIDs(ctx context.Context, reviewIds []int64) ([]GetTagsByReviewIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagsByReviewIDs, pq.Array(reviewIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsByReviewIDsRow
+	for rows.Next() {
+		var i GetTagsByReviewIDsRow
+		if err := rows.Scan(
+			&i.ReviewID,
+			&i.ID,
+			&i.Name,
+			&i.Sentiment,
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertReviewTag = `-- name: InsertReviewTag :exec
+INSERT INTO review_tag ("review_id", "tag_id") VALUES ($1, $2)
+`
+
+type InsertReviewTagParams struct {
+	ReviewID int64
+	TagID    int64
+}
+
+func (q *Queries) InsertReviewTag(ctx context.Context, arg InsertReviewTagParams) error {
+	_, err := q.db.ExecContext(ctx, insertReviewTag, arg.ReviewID, arg.TagID)
+	return err
+}
+
+const insertTag = `-- name: InsertTag :one
+INSERT INTO tag ("name", "sentiment")
+VALUES ($1, $2)
+RETURNING id, name, sentiment, active, created_at
+`
+
+type InsertTagParams struct {
+	Name      string
+	Sentiment string
+}
+
+func (q *Queries) InsertTag(ctx context.Context, arg InsertTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, insertTag, arg.Name, arg.Sentiment)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Sentiment,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listTags = `-- name: ListTags :many
+SELECT id, name, sentiment, active, created_at FROM tag
+WHERE "active" OR $1::boolean
+ORDER BY "id"
+`
+
+func (q *Queries) ListTags(ctx context.Context, includeInactive bool) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, listTags, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Sentiment,
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTag = `-- name: UpdateTag :one
+UPDATE tag
+SET
+    "name" = COALESCE($1, "name"),
+    "sentiment" = COALESCE($2, "sentiment"),
+    "active" = COALESCE($3, "active")
+WHERE "id" = $4
+RETURNING id, name, sentiment, active, created_at
+`
+
+type UpdateTagParams struct {
+	Name      sql.NullString
+	Sentiment sql.NullString
+	Active    sql.NullBool
+	ID        int64
+}
+
+// null arguments keep the current value
+func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, updateTag,
+		arg.Name,
+		arg.Sentiment,
+		arg.Active,
+		arg.ID,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Sentiment,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,91 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: vote.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const addReviewVoteCounts = `-- name: AddReviewVoteCounts :one
+UPDATE review
+SET
+    "helpful_count" = "helpful_count" + $2,
+    "not_helpful_count" = "not_helpful_count" + $3
+WHERE "id" = $1
+RETURNING "helpful_count", "not_helpful_count"
+`
+
+type AddReviewVoteCountsParams struct {
+	ID              int64
+	HelpfulDelta    int32
+	NotHelpfulDelta int32
+}
+
+type AddReviewVoteCountsRow struct {
+	HelpfulCount    int32
+	NotHelpfulCount int32
+}
+
+func (q *Queries) AddReviewVoteCounts(ctx context.Context, arg AddReviewVoteCountsParams) (AddReviewVoteCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, addReviewVoteCounts, arg.ID, arg.HelpfulDelta, arg.NotHelpfulDelta)
+	var i AddReviewVoteCountsRow
+	err := row.Scan(&i.HelpfulCount, &i.NotHelpfulCount)
+	return i, err
+}
+
+const deleteReviewVote = `-- name: DeleteReviewVote :exec
+DELETE FROM review_vote
+WHERE "review_id" = $1 AND "user_id" = $2
+`
+
+type DeleteReviewVoteParams struct {
+	ReviewID int64
+	UserID   int64
+}
+
+func (q *Queries) DeleteReviewVote(ctx context.Context, arg DeleteReviewVoteParams) error {
+	_, err := q.db.ExecContext(ctx, deleteReviewVote, arg.ReviewID, arg.UserID)
+	return err
+}
+
+const getReviewVote = `-- name: GetReviewVote :one
+SELECT "helpful" FROM review_vote
+WHERE "review_id" = $1 AND "user_id" = $2
+`
+
+type GetReviewVoteParams struct {
+	ReviewID int64
+	UserID   int64
+}
+
+func (q *Queries) GetReviewVote(ctx context.Context, arg GetReviewVoteParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getReviewVote, arg.ReviewID, arg.UserID)
+	var helpful bool
+	err := row.Scan(&helpful)
+	return helpful, err
+}
+
+const upsertReviewVote = `-- name: UpsertReviewVote :exec
+INSERT INTO
+    review_vote ("review_id", "user_id", "helpful")
+VALUES ($1, $2, $3)
+ON CONFLICT ("review_id", "user_id") DO
+UPDATE
+SET
+    "helpful" = excluded."helpful",
+    "updated_at" = now()
+`
+
+type UpsertReviewVoteParams struct {
+	ReviewID int64
+	UserID   int64
+	Helpful  bool
+}
+
+func (q *Queries) UpsertReviewVote(ctx context.Context, arg UpsertReviewVoteParams) error {
+	_, err := q.db.ExecContext(ctx, upsertReviewVote, arg.ReviewID, arg.UserID, arg.Helpful)
+	return err
+}
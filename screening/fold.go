@@ -0,0 +1,39 @@
+package screening
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fold lowers s and strips diacritics, e.g. "Giao Hàng Đúng Hẹn" becomes
+// "giao hang dung hen".
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, c := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, c):
+			// combining mark
+		case c == 'đ':
+			b.WriteRune('d')
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// lower lowers s and composes its diacritics, so that the same text typed
+// with combining marks or precomposed letters compares equal.
+func lower(s string) string {
+	return norm.NFC.String(strings.ToLower(s))
+}
+
+// tokenize splits s into words of letters and digits.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(s, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
+	})
+}
@@ -0,0 +1,37 @@
+package screening
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Giao Hàng Đúng Hẹn", "giao hang dung hen"},
+		{"ĐỊT MẸ", "dit me"},
+		{"óc chó", "oc cho"},
+		// combining marks instead of precomposed letters
+		{"o\u0301c cho\u0301", "oc cho"},
+		{"fück", "fuck"},
+		{"hello, world!", "hello, world!"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("tốt!!! giao-hàng, 5 sao")
+	want := []string{"tốt", "giao", "hàng", "5", "sao"}
+	if len(got) != len(want) {
+		t.Fatalf("tokenize() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("tokenize() = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package screening
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bannedWords matches words and phrases regardless of case. Words typed
+// without diacritics also match their accented spellings, so "fuck" catches
+// "fück", while words with diacritics only match that exact spelling: "óc
+// chó" must not catch "ốc chợ".
+type bannedWords struct {
+	action Action
+	// exact are matched on the lowered content, folded on the folded one
+	exact  []string
+	folded []string
+	// allowed are phrases which contain banned words without being
+	// offensive, e.g. "hạt óc chó" (walnuts)
+	allowed []string
+}
+
+// NewBannedWords returns a rule matching any of words, which may be phrases,
+// unless they are part of one of the allowed phrases.
+func NewBannedWords(action Action, words, allowed []string) Rule {
+	r := &bannedWords{action: action}
+	for _, w := range words {
+		exact := phrase(lower(w))
+		if exact == "" {
+			continue
+		}
+		if folded := phrase(Fold(w)); folded == exact {
+			r.folded = append(r.folded, folded)
+		} else {
+			r.exact = append(r.exact, exact)
+		}
+	}
+	for _, a := range allowed {
+		if p := phrase(lower(a)); p != "" {
+			r.allowed = append(r.allowed, p)
+		}
+	}
+	return r
+}
+
+// phrase joins the words of s, padded with spaces so that phrases only
+// match whole words.
+func phrase(s string) string {
+	words := tokenize(s)
+	if len(words) == 0 {
+		return ""
+	}
+	return " " + strings.Join(words, " ") + " "
+}
+
+func (r *bannedWords) Name() string { return "banned_words" }
+
+func (r *bannedWords) Check(s Submission) Verdict {
+	text := phrase(lower(s.Content))
+	for _, a := range r.allowed {
+		// keep a token in place of the phrase, so that the words around it
+		// don't form a banned phrase
+		text = strings.ReplaceAll(text, a, " \x00 ")
+	}
+	if matchesAny(text, r.exact) || matchesAny(Fold(text), r.folded) {
+		return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonBannedWord}
+	}
+	return Verdict{Action: Allow}
+}
+
+func matchesAny(text string, phrases []string) bool {
+	for _, p := range phrases {
+		if strings.Contains(text, p) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	urlPattern   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|vn|info|biz|io|me|xyz|shop)(?:\.vn)?\b`)
+	emailPattern = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)
+	// Vietnamese mobile and landline numbers, tolerating separators
+	phonePattern = regexp.MustCompile(`(?:\+?84|\b0)(?:[\s.-]?\d){8,10}\b`)
+)
+
+// contactInfo detects links, email addresses and phone numbers, which are
+// usually spam or leak personal data.
+type contactInfo struct {
+	action Action
+	urls   bool
+	emails bool
+	phones bool
+}
+
+// NewContactInfo returns a rule detecting the enabled kinds of contact info.
+func NewContactInfo(action Action, urls, emails, phones bool) Rule {
+	return &contactInfo{action: action, urls: urls, emails: emails, phones: phones}
+}
+
+func (r *contactInfo) Name() string { return "contact_info" }
+
+func (r *contactInfo) Check(s Submission) Verdict {
+	// emails are checked first since their domain also looks like a url
+	switch {
+	case r.emails && emailPattern.MatchString(s.Content):
+		return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonEmail}
+	case r.urls && urlPattern.MatchString(s.Content):
+		return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonURL}
+	case r.phones && phonePattern.MatchString(s.Content):
+		return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonPhone}
+	}
+	return Verdict{Action: Allow}
+}
+
+// shouting detects content written mostly in capital letters.
+type shouting struct {
+	action       Action
+	maxCapsRatio float64
+	minLetters   int
+}
+
+// NewShouting returns a rule matching content with at least minLetters
+// letters, more than maxCapsRatio of which are upper case.
+func NewShouting(action Action, maxCapsRatio float64, minLetters int) Rule {
+	return &shouting{action: action, maxCapsRatio: maxCapsRatio, minLetters: minLetters}
+}
+
+func (r *shouting) Name() string { return "shouting" }
+
+func (r *shouting) Check(s Submission) Verdict {
+	var letters, upper int
+	for _, c := range s.Content {
+		if unicode.IsLetter(c) {
+			letters++
+			if unicode.IsUpper(c) {
+				upper++
+			}
+		}
+	}
+	if letters >= r.minLetters && letters > 0 && float64(upper)/float64(letters) > r.maxCapsRatio {
+		return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonShouting}
+	}
+	return Verdict{Action: Allow}
+}
+
+// repetition detects runs of the same character ("!!!!!!!", "goooood") or
+// of the same word ("tốt tốt tốt tốt").
+type repetition struct {
+	action           Action
+	maxRepeatedChars int
+	maxRepeatedWords int
+}
+
+// NewRepetition returns a rule matching more than maxRepeatedChars
+// consecutive identical characters or more than maxRepeatedWords consecutive
+// identical words. A zero limit disables the check.
+func NewRepetition(action Action, maxRepeatedChars, maxRepeatedWords int) Rule {
+	return &repetition{action: action, maxRepeatedChars: maxRepeatedChars, maxRepeatedWords: maxRepeatedWords}
+}
+
+func (r *repetition) Name() string { return "repetition" }
+
+func (r *repetition) Check(s Submission) Verdict {
+	if r.maxRepeatedChars > 0 {
+		run, prev := 0, rune(-1)
+		for _, c := range s.Content {
+			if c == prev && !unicode.IsSpace(c) {
+				run++
+			} else {
+				run, prev = 1, c
+			}
+			if run > r.maxRepeatedChars {
+				return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonRepetition}
+			}
+		}
+	}
+	if r.maxRepeatedWords > 0 {
+		run, prev := 0, ""
+		for _, word := range tokenize(Fold(s.Content)) {
+			if word == prev {
+				run++
+			} else {
+				run, prev = 1, word
+			}
+			if run > r.maxRepeatedWords {
+				return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonRepetition}
+			}
+		}
+	}
+	return Verdict{Action: Allow}
+}
+
+// minLength requires longer content for some ratings, typically so that
+// low ratings explain what went wrong.
+type minLength struct {
+	action Action
+	byStar map[int32]int
+}
+
+// NewMinLength returns a rule requiring at least byStar[n] characters for
+// reviews of n stars.
+func NewMinLength(action Action, byStar map[string]int) (Rule, error) {
+	r := &minLength{action: action, byStar: make(map[int32]int, len(byStar))}
+	for star, length := range byStar {
+		n, err := strconv.ParseInt(star, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid star %q", star)
+		}
+		r.byStar[int32(n)] = length
+	}
+	return r, nil
+}
+
+func (r *minLength) Name() string { return "min_length" }
+
+func (r *minLength) Check(s Submission) Verdict {
+	if utf8.RuneCountInString(strings.TrimSpace(s.Content)) < r.byStar[s.NumStar] {
+		return Verdict{Action: r.action, Rule: r.Name(), Reason: ReasonTooShort}
+	}
+	return Verdict{Action: Allow}
+}
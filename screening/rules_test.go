@@ -0,0 +1,109 @@
+package screening
+
+import "testing"
+
+type ruleTest struct {
+	name    string
+	content string
+	numStar int32
+	want    Action
+}
+
+func runRuleTests(t *testing.T, rule Rule, tests []ruleTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := rule.Check(Submission{Content: tt.content, NumStar: tt.numStar})
+			if v.Action != tt.want {
+				t.Errorf("Check(%q) = %v, want %v", tt.content, v.Action, tt.want)
+			}
+			if v.Action != Allow && v.Rule != rule.Name() {
+				t.Errorf("Check(%q) rule = %q, want %q", tt.content, v.Rule, rule.Name())
+			}
+		})
+	}
+}
+
+func TestBannedWords(t *testing.T) {
+	rule := NewBannedWords(Reject, []string{"óc chó", "fuck", "địt mẹ"}, []string{"hạt óc chó"})
+	runRuleTests(t, rule, []ruleTest{
+		{"clean", "sản phẩm tốt, giao hàng nhanh", 5, Allow},
+		{"banned phrase", "đồ óc chó", 1, Reject},
+		{"upper case", "ĐỒ ÓC CHÓ", 1, Reject},
+		{"combining marks", "đồ o\u0301c cho\u0301", 1, Reject},
+		{"plain word with diacritics", "what the fück", 1, Reject},
+		{"plain word inside another", "fuckedup", 1, Allow},
+		{"phrase across punctuation", "địt... mẹ", 1, Reject},
+		// regression: the same letters with other diacritics are other words
+		{"other diacritics", "ốc chợ ngon", 5, Allow},
+		// regression: walnuts
+		{"allowed phrase", "hạt óc chó rang rất thơm", 5, Allow},
+		{"banned next to allowed", "hạt óc chó của thằng óc chó", 1, Reject},
+		{"phrase across allowed", "óc hạt óc chó chó", 5, Allow},
+	})
+}
+
+func TestContactInfo(t *testing.T) {
+	rule := NewContactInfo(Flag, true, true, true)
+	runRuleTests(t, rule, []ruleTest{
+		{"clean", "dùng 2 tuần vẫn ổn, pin 5000mAh", 5, Allow},
+		{"url", "mua rẻ hơn tại https://example.com/deal", 5, Flag},
+		{"bare domain", "xem thêm ở shopgiare.vn nhé", 5, Flag},
+		{"email", "liên hệ ban@example.com", 5, Flag},
+		{"mobile", "gọi 0912 345 678 để mua", 5, Flag},
+		{"international", "zalo +84912345678", 5, Flag},
+		{"short number", "giá 150000 đồng", 5, Allow},
+	})
+
+	emailsOnly := NewContactInfo(Flag, false, true, false)
+	v := emailsOnly.Check(Submission{Content: "ban@example.com"})
+	if v.Reason != ReasonEmail {
+		t.Errorf("email reason = %q, want %q", v.Reason, ReasonEmail)
+	}
+	runRuleTests(t, emailsOnly, []ruleTest{
+		{"disabled urls", "https://example.com", 5, Allow},
+		{"disabled phones", "0912345678", 5, Allow},
+	})
+}
+
+func TestShouting(t *testing.T) {
+	rule := NewShouting(Flag, 0.7, 12)
+	runRuleTests(t, rule, []ruleTest{
+		{"normal", "Sản phẩm rất TỐT, giao hàng nhanh", 5, Allow},
+		{"shouting", "SẢN PHẨM QUÁ TỆ, ĐỪNG MUA", 1, Flag},
+		{"short", "OK TỐT", 5, Allow},
+		{"no letters", "!!! 100 ???", 5, Allow},
+	})
+}
+
+func TestRepetition(t *testing.T) {
+	rule := NewRepetition(Flag, 6, 4)
+	runRuleTests(t, rule, []ruleTest{
+		{"normal", "tốt tốt, rất đáng tiền!!!", 5, Allow},
+		{"repeated chars", "quá tệ!!!!!!!!", 1, Flag},
+		{"repeated letters", "goooooooood", 5, Flag},
+		{"spaces", "tốt        lắm", 5, Allow},
+		{"repeated words", "tốt tốt tốt tốt tốt", 5, Flag},
+		{"repeated words with diacritics", "TỐT tốt tot Tốt tốt", 5, Flag},
+	})
+	runRuleTests(t, NewRepetition(Flag, 0, 0), []ruleTest{
+		{"disabled", "tốt tốt tốt tốt tốt!!!!!!!!", 5, Allow},
+	})
+}
+
+func TestMinLength(t *testing.T) {
+	rule, err := NewMinLength(Reject, map[string]int{"1": 15, "2": 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runRuleTests(t, rule, []ruleTest{
+		{"low rating too short", "tệ quá", 1, Reject},
+		{"low rating explained", "pin tụt rất nhanh, không nên mua", 2, Allow},
+		{"padded with spaces", "   tệ quá      ", 1, Reject},
+		{"high rating", "tốt", 5, Allow},
+	})
+
+	if _, err := NewMinLength(Reject, map[string]int{"one": 15}); err == nil {
+		t.Error("invalid star accepted")
+	}
+}
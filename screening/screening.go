@@ -0,0 +1,194 @@
+// Package screening checks review content against an ordered chain of rules
+// before it is stored.
+package screening
+
+import (
+	_ "embed" // default rule set
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Action is the outcome of a rule.
+type Action int
+
+const (
+	// Allow lets the content through.
+	Allow Action = iota
+	// Flag stores the content but holds it for moderation.
+	Flag
+	// Reject refuses the content.
+	Reject
+)
+
+func (a Action) String() string {
+	switch a {
+	case Allow:
+		return "allow"
+	case Flag:
+		return "flag"
+	case Reject:
+		return "reject"
+	default:
+		return fmt.Sprintf("Action(%d)", int(a))
+	}
+}
+
+// UnmarshalText parses "allow", "flag" or "reject".
+func (a *Action) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "allow":
+		*a = Allow
+	case "flag":
+		*a = Flag
+	case "reject":
+		*a = Reject
+	default:
+		return fmt.Errorf("unknown action %q", text)
+	}
+	return nil
+}
+
+// Reason codes reported by the built-in rules.
+const (
+	ReasonBannedWord = "banned_word"
+	ReasonURL        = "url"
+	ReasonEmail      = "email"
+	ReasonPhone      = "phone"
+	ReasonShouting   = "shouting"
+	ReasonRepetition = "repetition"
+	ReasonTooShort   = "too_short"
+)
+
+// Submission is the content being screened.
+type Submission struct {
+	Content string
+	NumStar int32
+}
+
+// Verdict is the decision of a rule. Rule and Reason are empty for Allow.
+type Verdict struct {
+	Action Action
+	Rule   string
+	Reason string
+}
+
+// Rule checks a submission.
+type Rule interface {
+	Name() string
+	Check(s Submission) Verdict
+}
+
+// Pipeline runs rules in order. The first rejection stops the chain;
+// otherwise the first flag, if any, is the overall verdict.
+type Pipeline struct {
+	rules []Rule
+}
+
+// NewPipeline returns a pipeline running rules in the given order.
+func NewPipeline(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+// Screen returns the overall verdict for the submission.
+func (p *Pipeline) Screen(s Submission) Verdict {
+	result := Verdict{Action: Allow}
+	for _, rule := range p.rules {
+		v := rule.Check(s)
+		switch {
+		case v.Action == Reject:
+			return v
+		case v.Action == Flag && result.Action == Allow:
+			result = v
+		}
+	}
+	return result
+}
+
+// Config is the file format of a rule set: rules run in the listed order.
+type Config struct {
+	Rules []RuleConfig `json:"rules"`
+}
+
+// RuleConfig configures one rule. Type selects the rule and decides which
+// of the other fields apply.
+type RuleConfig struct {
+	Type   string `json:"type"`
+	Action Action `json:"action"`
+
+	// banned_words
+	Words   []string `json:"words,omitempty"`
+	Allowed []string `json:"allowed,omitempty"`
+
+	// contact_info
+	URLs   bool `json:"urls,omitempty"`
+	Emails bool `json:"emails,omitempty"`
+	Phones bool `json:"phones,omitempty"`
+
+	// shouting
+	MaxCapsRatio float64 `json:"max_caps_ratio,omitempty"`
+	MinLetters   int     `json:"min_letters,omitempty"`
+
+	// repetition
+	MaxRepeatedChars int `json:"max_repeated_chars,omitempty"`
+	MaxRepeatedWords int `json:"max_repeated_words,omitempty"`
+
+	// min_length, keyed by number of stars
+	MinLengthByStar map[string]int `json:"min_length_by_star,omitempty"`
+}
+
+// Load builds a pipeline from the JSON rule set in data.
+func Load(data []byte) (*Pipeline, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse screening rules: %w", err)
+	}
+	rules := make([]Rule, 0, len(cfg.Rules))
+	for i, rc := range cfg.Rules {
+		rule, err := newRule(rc)
+		if err != nil {
+			return nil, fmt.Errorf("screening rule %d: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return NewPipeline(rules...), nil
+}
+
+// LoadFile builds a pipeline from a JSON rule set file.
+func LoadFile(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
+
+func newRule(rc RuleConfig) (Rule, error) {
+	switch rc.Type {
+	case "banned_words":
+		return NewBannedWords(rc.Action, rc.Words, rc.Allowed), nil
+	case "contact_info":
+		return NewContactInfo(rc.Action, rc.URLs, rc.Emails, rc.Phones), nil
+	case "shouting":
+		return NewShouting(rc.Action, rc.MaxCapsRatio, rc.MinLetters), nil
+	case "repetition":
+		return NewRepetition(rc.Action, rc.MaxRepeatedChars, rc.MaxRepeatedWords), nil
+	case "min_length":
+		return NewMinLength(rc.Action, rc.MinLengthByStar)
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", rc.Type)
+	}
+}
+
+//go:embed default_rules.json
+var defaultRules []byte
+
+// Default returns the pipeline of the built-in rule set.
+func Default() *Pipeline {
+	p, err := Load(defaultRules)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
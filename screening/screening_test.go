@@ -0,0 +1,93 @@
+package screening
+
+import "testing"
+
+// fixedRule returns the same verdict for every submission.
+type fixedRule struct {
+	name   string
+	action Action
+}
+
+func (r fixedRule) Name() string { return r.name }
+
+func (r fixedRule) Check(Submission) Verdict {
+	if r.action == Allow {
+		return Verdict{Action: Allow}
+	}
+	return Verdict{Action: r.action, Rule: r.name, Reason: r.name}
+}
+
+func TestPipelineScreen(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []Rule
+		want     Action
+		wantRule string
+	}{
+		{"no rules", nil, Allow, ""},
+		{"all allow", []Rule{fixedRule{"a", Allow}, fixedRule{"b", Allow}}, Allow, ""},
+		{"first flag wins", []Rule{fixedRule{"a", Allow}, fixedRule{"b", Flag}, fixedRule{"c", Flag}}, Flag, "b"},
+		{"reject after flag", []Rule{fixedRule{"a", Flag}, fixedRule{"b", Reject}}, Reject, "b"},
+		{"reject stops the chain", []Rule{fixedRule{"a", Reject}, fixedRule{"b", Reject}}, Reject, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewPipeline(tt.rules...).Screen(Submission{})
+			if v.Action != tt.want || v.Rule != tt.wantRule {
+				t.Errorf("Screen() = %v by %q, want %v by %q", v.Action, v.Rule, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load([]byte(`{"rules": [
+		{"type": "banned_words", "action": "flag", "words": ["spam"], "allowed": ["no spam"]},
+		{"type": "min_length", "action": "REJECT", "min_length_by_star": {"1": 10}}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := p.Screen(Submission{Content: "this is spam", NumStar: 5}); v.Action != Flag || v.Reason != ReasonBannedWord {
+		t.Errorf("banned word: got %+v", v)
+	}
+	if v := p.Screen(Submission{Content: "no spam here", NumStar: 5}); v.Action != Allow {
+		t.Errorf("allowed phrase: got %+v", v)
+	}
+	if v := p.Screen(Submission{Content: "bad", NumStar: 1}); v.Action != Reject || v.Reason != ReasonTooShort {
+		t.Errorf("too short: got %+v", v)
+	}
+
+	for _, invalid := range []string{
+		`{"rules": [{"type": "sentiment", "action": "flag"}]}`,
+		`{"rules": [{"type": "shouting", "action": "block"}]}`,
+		`{"rules": [{"type": "min_length", "action": "reject", "min_length_by_star": {"x": 1}}]}`,
+		`not json`,
+	} {
+		if _, err := Load([]byte(invalid)); err == nil {
+			t.Errorf("Load(%s) accepted", invalid)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	p := Default()
+	tests := []struct {
+		name    string
+		content string
+		numStar int32
+		want    Action
+	}{
+		{"clean", "Hạt óc chó rang giòn, đóng gói cẩn thận, sẽ mua lại", 5, Allow},
+		{"offensive", "shop óc chó, giao sai hàng mà không chịu đổi", 1, Reject},
+		{"contact info", "mua rẻ hơn ở shopgiare.vn", 5, Flag},
+		{"too short", "tệ", 1, Reject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := p.Screen(Submission{Content: tt.content, NumStar: tt.numStar}); v.Action != tt.want {
+				t.Errorf("Screen(%q) = %+v, want %v", tt.content, v, tt.want)
+			}
+		})
+	}
+}
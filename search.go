@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+const maxSearchQueryLength = 200
+
+// delimiters of the matches in snippets returned by the database
+const (
+	highlightStart = '\x02'
+	highlightStop  = '\x03'
+)
+
+func (srv reviewService) SearchReviews(ctx context.Context, req *pb.SearchReviewsRequest) (*pb.SearchReviewsResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	minStar, maxStar := req.GetMinStar(), req.GetMaxStar()
+	if minStar == 0 {
+		minStar = 1
+	}
+	if maxStar == 0 {
+		maxStar = 5
+	}
+
+	var violations []fieldViolation
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		violations = append(violations, fieldViolation{field: "query", description: msgSearchQueryLength, args: []interface{}{maxSearchQueryLength}})
+	}
+	if minStar < 1 || minStar > 5 {
+		violations = append(violations, fieldViolation{field: "min_star", description: msgNumStarRange, args: []interface{}{1, 5}})
+	}
+	if maxStar < minStar || maxStar > 5 {
+		violations = append(violations, fieldViolation{field: "max_star", description: msgNumStarRange, args: []interface{}{minStar, 5}})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	afterRank, afterID, err := decodeRankedPageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	rows, err := srv.queries.SearchReviews(ctx, repository.SearchReviewsParams{
+		Query:     query,
+		ProductID: req.GetProductId(),
+		MinStar:   minStar,
+		MaxStar:   maxStar,
+		AfterRank: afterRank,
+		AfterID:   afterID,
+		PageSize:  pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &pb.SearchReviewsResponse{}, nil
+	}
+
+	reviews := make([]repository.Review, 0, len(rows))
+	for _, row := range rows {
+		reviews = append(reviews, repository.Review{
+			ID:              row.ID,
+			UserID:          row.UserID,
+			ProductID:       row.ProductID,
+			NumStar:         row.NumStar,
+			Content:         row.Content,
+			Status:          row.Status,
+			HelpfulCount:    row.HelpfulCount,
+			NotHelpfulCount: row.NotHelpfulCount,
+			CommentCount:    row.CommentCount,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+	pbReviews, err := srv.toPbReviews(ctx, reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.SearchReviewsResponse{}
+	for i, row := range rows {
+		resp.ListResult = append(resp.ListResult, &pb.SearchResult{
+			Review:  pbReviews[i],
+			Snippet: highlightSnippet(row.Snippet),
+			Rank:    row.Rank,
+		})
+	}
+	if len(rows) == int(pageSize) {
+		last := rows[len(rows)-1]
+		resp.NextPageToken = encodeRankedPageToken(last.Rank, last.ID)
+	}
+	return resp, nil
+}
+
+// highlightSnippet escapes a snippet, which is review content, and turns the
+// match delimiters into <mark> tags. Delimiters typed in the content itself
+// can't add unbalanced tags.
+func highlightSnippet(snippet string) string {
+	var b strings.Builder
+	open := false
+	for _, r := range html.EscapeString(snippet) {
+		switch {
+		case r == highlightStart && !open:
+			b.WriteString("<mark>")
+			open = true
+		case r == highlightStop && open:
+			b.WriteString("</mark>")
+			open = false
+		case r == highlightStart, r == highlightStop:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if open {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{"plain", "giao hàng \x02nhanh\x03", "giao hàng <mark>nhanh</mark>"},
+		{"markup in content", "<script>alert(1)</script> \x02good\x03 & \"cheap\"", "&lt;script&gt;alert(1)&lt;/script&gt; <mark>good</mark> &amp; &#34;cheap&#34;"},
+		{"typed mark tags", "<mark>fake</mark> \x02real\x03", "&lt;mark&gt;fake&lt;/mark&gt; <mark>real</mark>"},
+		{"stray delimiters", "\x03a \x02b \x02c\x03 \x03d \x02e", "a <mark>b c</mark> d <mark>e</mark>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(tt.snippet); got != tt.want {
+				t.Errorf("highlightSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,62 +2,153 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"io"
 	"log"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/e-commerce-microservices/review-service/pb"
 	"github.com/e-commerce-microservices/review-service/repository"
+	"github.com/e-commerce-microservices/review-service/screening"
 	"github.com/golang/protobuf/ptypes/empty"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 )
 
 type reviewService struct {
+	db          *sql.DB
 	queries     *repository.Queries
 	authClient  pb.AuthServiceClient
 	orderClient pb.OrderServiceClient
 	imageClient pb.ImageServiceClient
+	idempotency idempotencyStore
+	screener    *screening.Pipeline
+	// autoApprove publishes content that passes screening without moderation
+	autoApprove bool
+	// createReviewTimeout bounds CreateReview including its image uploads,
+	// shutdown drains in-flight rpcs for longer than that
+	createReviewTimeout time.Duration
+	// reportThreshold is the number of reports hiding a review, 0 disables it
+	reportThreshold int
+	dimensions      ratingDimensions
+	// broker fans review changes out to WatchProductReviews streams
+	broker *reviewBroker
 	pb.UnimplementedReviewServiceServer
 }
 
 var _empty = &empty.Empty{}
 
+// execTx runs fn in a transaction which is rolled back when fn fails.
+func (srv reviewService) execTx(ctx context.Context, fn func(*repository.Queries) error) error {
+	tx, err := srv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(srv.queries.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Println("error when rollback: ", rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
 func (srv reviewService) CreateReview(ctx context.Context, req *pb.CreateReviewRequest) (*pb.CreateReviewResponse, error) {
-	// extract md
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, errors.New("invalid request")
+	if err := validateCreateReview(req, srv.dimensions); err != nil {
+		return nil, err
 	}
-	// inject md
-	ctx = metadata.NewOutgoingContext(ctx, md)
 
-	// check order is handled
-	resp, err := srv.orderClient.CheckOrderIsHandled(ctx, &pb.CheckOrderIsHandledRequest{
-		ProductId: req.GetProductId(),
-	})
-	log.Println("product id: ", req.GetProductId())
+	caller, err := mustPrincipal(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if !resp.GetIsBought() {
-		return nil, errors.New("Sản phẩm này chưa được mua")
+
+	// retried requests replay the response of the first one
+	key := idempotencyKeyFrom(ctx, req.GetIdempotencyKey())
+	if key == "" {
+		return srv.createReview(ctx, caller, req)
+	}
+	fingerprint, err := requestFingerprint(req, func(m proto.Message) {
+		m.(*pb.CreateReviewRequest).IdempotencyKey = ""
+	})
+	if err != nil {
+		return nil, err
 	}
+	return runIdempotent(ctx, srv.idempotency, idempotentCall{
+		userID:      caller.userID,
+		method:      "CreateReview",
+		key:         key,
+		fingerprint: fingerprint,
+	}, func() *pb.CreateReviewResponse {
+		return &pb.CreateReviewResponse{}
+	}, func() (*pb.CreateReviewResponse, error) {
+		return srv.createReview(ctx, caller, req)
+	})
+}
+
+func (srv reviewService) createReview(ctx context.Context, caller principal, req *pb.CreateReviewRequest) (*pb.CreateReviewResponse, error) {
+	// images are uploaded one after the other, each with its own timeout, so
+	// the whole call gets a deadline too; remaining uploads are skipped
+	ctx, cancel := context.WithTimeout(ctx, srv.createReviewTimeout)
+	defer cancel()
 
-	// auth
-	claims, err := srv.authClient.GetUserClaims(ctx, _empty)
+	status, reason, err := srv.screenContent("content", req.GetContent(), req.GetNumStar())
+	if err != nil {
+		return nil, err
+	}
+	tags, err := srv.checkReviewTags(ctx, req.GetTagIds())
 	if err != nil {
 		return nil, err
 	}
 
-	id, _ := strconv.ParseInt(claims.GetId(), 10, 64)
+	// check order is handled
+	resp, err := srv.orderClient.CheckOrderIsHandled(forwardMetadata(ctx), &pb.CheckOrderIsHandledRequest{
+		ProductId: req.GetProductId(),
+	})
+	if err != nil {
+		return nil, fromDependency("order-service", err)
+	}
+	if !resp.GetIsBought() {
+		return nil, errNotPurchased(req.GetProductId())
+	}
 
-	review, err := srv.queries.InsertReview(ctx, repository.InsertReviewParams{
-		UserID:    id,
-		ProductID: req.GetProductId(),
-		NumStar:   int32(req.GetNumStar()),
-		Content:   req.GetContent(),
+	var review repository.Review
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		review, err = q.InsertReview(ctx, repository.InsertReviewParams{
+			UserID:           caller.userID,
+			ProductID:        req.GetProductId(),
+			NumStar:          int32(req.GetNumStar()),
+			Content:          req.GetContent(),
+			Status:           status,
+			ModerationReason: reason.String(),
+			CategoryID:       req.GetCategoryId(),
+		})
+		if err != nil {
+			return err
+		}
+		for _, subRating := range req.GetSubRatings() {
+			err = q.InsertSubRating(ctx, repository.InsertSubRatingParams{
+				ReviewID:  review.ID,
+				Dimension: subRating.GetDimension(),
+				Rating:    subRating.GetRating(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, tag := range tags {
+			err = q.InsertReviewTag(ctx, repository.InsertReviewTagParams{
+				ReviewID: review.ID,
+				TagID:    tag.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return enqueueReviewEvent(ctx, q, eventReviewCreated, "", review)
 	})
 	if err != nil {
 		return nil, err
@@ -65,7 +156,7 @@ func (srv reviewService) CreateReview(ctx context.Context, req *pb.CreateReviewR
 
 	listImage := []string{}
 	for _, dataChunk := range req.GetImageDataChunk() {
-		thumbnail, err := uploadImage(ctx, dataChunk, srv.imageClient)
+		thumbnail, err := uploadImage(forwardMetadata(ctx), dataChunk, srv.imageClient)
 		if err != nil {
 			log.Println("error when upload image: ", err)
 			continue
@@ -80,39 +171,43 @@ func (srv reviewService) CreateReview(ctx context.Context, req *pb.CreateReviewR
 	}
 
 	// bought
+	message := localize(ctx, msgReviewCreated)
+	if review.Status == repository.ReviewStatusPending {
+		message = localize(ctx, msgReviewPendingModeration)
+	}
+	pbReview := toPbReview(review, listImage)
+	pbReview.SubRatings = req.GetSubRatings()
+	pbReview.Tags = toPbTags(tags)
 	return &pb.CreateReviewResponse{
-		Message: "Thêm review thành công",
-		Review: &pb.Review{
-			ReviewId:  review.ID,
-			UserId:    id,
-			ProductId: req.GetProductId(),
-			ImageUrl:  listImage,
-			NumStar:   review.NumStar,
-			Content:   review.Content,
-		},
+		Message: message,
+		Review:  pbReview,
 	}, nil
 }
 
 func (srv reviewService) GetAllReviewByProductID(ctx context.Context, req *pb.GetAllReviewByProductIDRequest) (*pb.GetAllReviewByProductIDResponse, error) {
 
-	reviews, err := srv.queries.GetAllReviewByProductID(ctx, req.GetProductId())
+	var reviews []repository.Review
+	var err error
+	tagIDs := uniqueIDs(req.GetTagIds())
+	switch req.GetSortOrder() {
+	case pb.ReviewSortOrder_most_helpful:
+		reviews, err = srv.queries.GetMostHelpfulReviewsByProductID(ctx, repository.GetMostHelpfulReviewsByProductIDParams{
+			ProductID: req.GetProductId(),
+			TagIds:    tagIDs,
+		})
+	default:
+		reviews, err = srv.queries.GetAllReviewByProductID(ctx, repository.GetAllReviewByProductIDParams{
+			ProductID: req.GetProductId(),
+			TagIds:    tagIDs,
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*pb.Review, 0, len(reviews))
-	for _, review := range reviews {
-		// get image
-		images, _ := srv.queries.GetImagesByOrderID(ctx, review.ID)
-
-		result = append(result, &pb.Review{
-			ReviewId:  review.ID,
-			UserId:    review.UserID,
-			ProductId: review.ProductID,
-			ImageUrl:  images,
-			NumStar:   review.NumStar,
-			Content:   review.Content,
-		})
+	result, err := srv.toPbReviews(ctx, reviews)
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.GetAllReviewByProductIDResponse{
@@ -120,20 +215,234 @@ func (srv reviewService) GetAllReviewByProductID(ctx context.Context, req *pb.Ge
 	}, nil
 }
 
+func (srv reviewService) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewResponse, error) {
+	review, err := srv.queries.GetReviewByID(ctx, req.GetReviewId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound("review", req.GetReviewId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	// unpublished reviews are only disclosed to their author and moderators
+	if review.Status != repository.ReviewStatusApproved {
+		caller, ok := principalFromContext(ctx)
+		if !ok || (caller.userID != review.UserID && !caller.isAdmin()) {
+			return nil, errNotFound("review", req.GetReviewId())
+		}
+	}
+
+	result, err := srv.toPbReviews(ctx, []repository.Review{review})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetReviewResponse{
+		Review: result[0],
+	}, nil
+}
+
+func (srv reviewService) GetReviews(ctx context.Context, req *pb.GetReviewsRequest) (*pb.GetReviewsResponse, error) {
+	ids := uniqueIDs(req.GetReviewIds())
+	if len(ids) == 0 || len(ids) > maxPageSize {
+		return nil, errInvalidArgument(fieldViolation{field: "review_ids", description: msgReviewIDsCount, args: []interface{}{maxPageSize}})
+	}
+
+	reviews, err := srv.queries.GetReviewsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]repository.Review, len(reviews))
+	for _, review := range reviews {
+		if review.Status == repository.ReviewStatusApproved {
+			byID[review.ID] = review
+		}
+	}
+
+	resp := &pb.GetReviewsResponse{}
+	published := make([]repository.Review, 0, len(byID))
+	for _, id := range ids {
+		review, ok := byID[id]
+		if !ok {
+			resp.NotFoundReviewIds = append(resp.NotFoundReviewIds, id)
+			continue
+		}
+		published = append(published, review)
+	}
+	resp.ListReview, err = srv.toPbReviews(ctx, published)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// toPbReviews converts reviews and attaches their images, sub-ratings, tags
+// and seller replies.
+func (srv reviewService) toPbReviews(ctx context.Context, reviews []repository.Review) ([]*pb.Review, error) {
+	ids := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.ID)
+	}
+	replies, err := srv.queries.GetRepliesByReviewIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	replyByReview := make(map[int64]repository.ReviewReply, len(replies))
+	for _, reply := range replies {
+		replyByReview[reply.ReviewID] = reply
+	}
+	subRatings, err := srv.queries.GetSubRatingsByReviewIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	subRatingsByReview := make(map[int64][]repository.ReviewSubRating)
+	for _, subRating := range subRatings {
+		subRatingsByReview[subRating.ReviewID] = append(subRatingsByReview[subRating.ReviewID], subRating)
+	}
+	tags, err := srv.queries.GetTagsByReviewIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tagsByReview := make(map[int64][]*pb.Tag)
+	for _, tag := range tags {
+		tagsByReview[tag.ReviewID] = append(tagsByReview[tag.ReviewID], toPbTag(repository.Tag{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			Sentiment: tag.Sentiment,
+			Active:    tag.Active,
+		}))
+	}
+
+	result := make([]*pb.Review, 0, len(reviews))
+	for _, review := range reviews {
+		// get image
+		images, err := srv.queries.GetImagesByOrderID(ctx, review.ID)
+		if err != nil {
+			return nil, err
+		}
+		pbReview := toPbReview(review, images)
+		if reply, ok := replyByReview[review.ID]; ok {
+			pbReview.Reply = toPbReply(reply)
+		}
+		pbReview.SubRatings = toPbSubRatings(subRatingsByReview[review.ID])
+		pbReview.Tags = tagsByReview[review.ID]
+		result = append(result, pbReview)
+	}
+	return result, nil
+}
+
+func toPbReview(review repository.Review, images []string) *pb.Review {
+	return &pb.Review{
+		ReviewId:        review.ID,
+		UserId:          review.UserID,
+		ProductId:       review.ProductID,
+		ImageUrl:        images,
+		NumStar:         review.NumStar,
+		Content:         review.Content,
+		Status:          toPbStatus(review.Status),
+		HelpfulCount:    review.HelpfulCount,
+		NotHelpfulCount: review.NotHelpfulCount,
+		CommentCount:    review.CommentCount,
+	}
+}
+
 func (srv reviewService) DeleteReview(ctx context.Context, req *pb.DeleteReviewRequest) (*pb.DeleteReviewResponse, error) {
-	err := srv.queries.DeleteReview(ctx, req.GetReviewId())
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the author or an admin can delete a review
+	review, err := srv.queries.GetReviewByID(ctx, req.GetReviewId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound("review", req.GetReviewId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != caller.userID && !caller.isAdmin() {
+		return nil, errForbidden("NOT_REVIEW_AUTHOR")
+	}
+
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		if err := q.DeleteReview(ctx, review.ID); err != nil {
+			return err
+		}
+		return enqueueReviewEvent(ctx, q, eventReviewDeleted, review.Status, review)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &pb.DeleteReviewResponse{
-		Message: "Xóa thành công",
+		Message: localize(ctx, msgReviewDeleted),
 	}, nil
 }
 
-func (srv reviewService) UpdateReview(context.Context, *pb.UpdateReviewRequest) (*pb.UpdateReviewResponse, error) {
+func (srv reviewService) UpdateReview(ctx context.Context, req *pb.UpdateReviewRequest) (*pb.UpdateReviewResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []fieldViolation
+	if req.GetReviewId() <= 0 {
+		violations = append(violations, fieldViolation{field: "review_id", description: msgReviewIDInvalid})
+	}
+	if strings.TrimSpace(req.GetNewReview()) == "" {
+		violations = append(violations, fieldViolation{field: "new_review", description: msgContentEmpty})
+	}
+	if len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	var updated repository.Review
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		review, err := q.GetReviewForUpdate(ctx, req.GetReviewId())
+		if errors.Is(err, sql.ErrNoRows) {
+			return errNotFound("review", req.GetReviewId())
+		}
+		if err != nil {
+			return err
+		}
+		if review.UserID != caller.userID {
+			return errForbidden("NOT_REVIEW_AUTHOR")
+		}
+
+		// edited content is screened again like a new review
+		status, reason, err := srv.screenContent("new_review", req.GetNewReview(), review.NumStar)
+		if err != nil {
+			return err
+		}
+		status = editedStatus(review.Status, status)
+		updated, err = q.UpdateReviewContent(ctx, repository.UpdateReviewContentParams{
+			ID:      review.ID,
+			Content: req.GetNewReview(),
+		})
+		if err != nil {
+			return err
+		}
+		if status == review.Status && reason == pb.ModerationReason_no_reason {
+			return enqueueReviewEvent(ctx, q, eventReviewUpdated, review.Status, updated)
+		}
+		// the status change publishes the edited review
+		edited := review
+		edited.Content = updated.Content
+		updated.Status = status
+		return changeReviewStatus(ctx, q, edited, statusChange{
+			to:     status,
+			reason: reason,
+			note:   "content edited",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := localize(ctx, msgReviewUpdated)
+	if updated.Status == repository.ReviewStatusPending {
+		message = localize(ctx, msgReviewUpdatedPendingModeration)
+	}
 	return &pb.UpdateReviewResponse{
-		Message: "Cập nhật thành công",
+		Message: message,
 	}, nil
 }
 
@@ -143,23 +452,67 @@ func (srv reviewService) Ping(ctx context.Context, _ *empty.Empty) (*pb.Pong, er
 	}, nil
 }
 
-func toBytes(str string) []byte {
-	bytes, err := base64.StdEncoding.DecodeString(str)
+// validateCreateReview checks the request before any dependency is called.
+func validateCreateReview(req *pb.CreateReviewRequest, dims ratingDimensions) error {
+	var violations []fieldViolation
+	if req.GetProductId() <= 0 {
+		violations = append(violations, fieldViolation{field: "product_id", description: msgProductIDInvalid})
+	}
+	if req.GetNumStar() < 1 || req.GetNumStar() > 5 {
+		violations = append(violations, fieldViolation{field: "num_star", description: msgNumStarRange, args: []interface{}{1, 5}})
+	}
+	for i, dataChunk := range req.GetImageDataChunk() {
+		field := fmt.Sprintf("image_data_chunk[%d]", i)
+		_, _, err := parseDataURL(dataChunk)
+		switch {
+		case errors.Is(err, errBadDataURL):
+			violations = append(violations, fieldViolation{field: field, description: msgImageDataURL})
+		case errors.Is(err, errBadBase64):
+			violations = append(violations, fieldViolation{field: field, description: msgImageBase64})
+		}
+	}
+	if len(req.GetTagIds()) > maxReviewTags {
+		violations = append(violations, fieldViolation{field: "tag_ids", description: msgTagCount, args: []interface{}{maxReviewTags}})
+	}
+	violations = append(violations, dims.validateSubRatings(req.GetCategoryId(), req.GetSubRatings())...)
+	if len(violations) > 0 {
+		return errInvalidArgument(violations...)
+	}
+	return nil
+}
+
+var (
+	errBadDataURL = errors.New("image is not a base64 image data url")
+	errBadBase64  = errors.New("image data is not valid base64")
+)
+
+// parseDataURL splits a base64 image data URL such as
+// "data:image/png;base64,iVBORw0..." into its mime type and decoded bytes.
+func parseDataURL(dataChunk string) (string, []byte, error) {
+	header, data, ok := strings.Cut(dataChunk, ",")
+	if !ok || !strings.HasPrefix(header, "data:image/") {
+		return "", nil, errBadDataURL
+	}
+	mimeType := strings.Split(strings.TrimPrefix(header, "data:image/"), ";")[0]
+	bytes, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
-		log.Fatal(err)
+		return "", nil, errBadBase64
 	}
-	return bytes
+	return mimeType, bytes, nil
 }
 
 func uploadImage(ctx context.Context, dataChunk string, imageClient pb.ImageServiceClient) (string, error) {
+	mimeType, data, err := parseDataURL(dataChunk)
+	if err != nil {
+		return "", err
+	}
+
 	// upload image
 	stream, err := imageClient.UploadImage(ctx)
 	if err != nil {
 		return "", err
 	}
 	// send mime type
-	tmp := strings.Split(dataChunk, "data:image/")
-	mimeType := strings.Split(tmp[1], ";")[0]
 	err = stream.Send(&pb.UploadImageRequest{
 		Data: &pb.UploadImageRequest_Info{
 			Info: &pb.ImageInfo{
@@ -167,18 +520,19 @@ func uploadImage(ctx context.Context, dataChunk string, imageClient pb.ImageServ
 			},
 		},
 	})
-	if err != nil {
+	// send data, unless the server already aborted the stream
+	if err == nil {
+		err = stream.Send(&pb.UploadImageRequest{
+			Data: &pb.UploadImageRequest_ChunkData{
+				ChunkData: data,
+			},
+		})
+	}
+	// io.EOF means the server aborted the stream, its status is returned by CloseAndRecv
+	if err != nil && !errors.Is(err, io.EOF) {
 		return "", err
 	}
 
-	// send data
-	dataChunk = strings.Split(dataChunk, ",")[1]
-	stream.Send(&pb.UploadImageRequest{
-		Data: &pb.UploadImageRequest_ChunkData{
-			ChunkData: toBytes(dataChunk),
-		},
-	})
-
 	res, err := stream.CloseAndRecv()
 	if err != nil {
 		return "", err
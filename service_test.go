@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetReviewDisclosesUnpublishedToAuthorAndAdmins(t *testing.T) {
+	db := &fakeModerationDB{
+		reviews: []repository.Review{
+			{ID: 1, UserID: 10, ProductID: 5, NumStar: 5, Status: repository.ReviewStatusApproved, CreatedAt: time.Now()},
+			{ID: 2, UserID: 10, ProductID: 5, NumStar: 1, Status: repository.ReviewStatusHidden, CreatedAt: time.Now()},
+		},
+	}
+	srv := db.service(0)
+
+	anonymous := context.Background()
+	author := withPrincipal(context.Background(), principal{userID: 10, role: pb.UserRole_customer})
+	other := withPrincipal(context.Background(), principal{userID: 11, role: pb.UserRole_customer})
+	admin := withPrincipal(context.Background(), principal{userID: 1, role: pb.UserRole_admin})
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		reviewID int64
+		wantCode codes.Code
+	}{
+		{"published to anonymous", anonymous, 1, codes.OK},
+		{"hidden to anonymous", anonymous, 2, codes.NotFound},
+		{"hidden to another user", other, 2, codes.NotFound},
+		{"hidden to its author", author, 2, codes.OK},
+		{"hidden to an admin", admin, 2, codes.OK},
+		{"missing to an admin", admin, 3, codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := srv.GetReview(tt.ctx, &pb.GetReviewRequest{ReviewId: tt.reviewID})
+			if code := status.Code(toStatusError(tt.ctx, "GetReview", err)); code != tt.wantCode {
+				t.Fatalf("got %v, want %v", code, tt.wantCode)
+			}
+			if err == nil && resp.GetReview().GetReviewId() != tt.reviewID {
+				t.Errorf("got review %d, want %d", resp.GetReview().GetReviewId(), tt.reviewID)
+			}
+		})
+	}
+}
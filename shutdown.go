@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// gracefulStop stops the server from accepting new RPCs and waits for the
+// in-flight ones to finish. Once timeout elapses the server is force-stopped,
+// which cancels the remaining RPCs.
+func gracefulStop(srv *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("all in-flight rpcs completed")
+	case <-time.After(timeout):
+		log.Printf("graceful stop timed out after %s, forcing stop", timeout)
+		srv.Stop()
+		<-done
+	}
+}
+
+type namedCloser struct {
+	name   string
+	closer io.Closer
+}
+
+// closeAll closes the given resources in order, logging failures.
+func closeAll(closers ...namedCloser) {
+	for _, c := range closers {
+		if err := c.closer.Close(); err != nil {
+			log.Printf("error when close %s: %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// blockingHealthServer blocks Check until released or canceled.
+type blockingHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	close(s.started)
+	select {
+	case <-s.release:
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startBlockingCheck serves a blocking health server and starts a Check,
+// returning once the rpc is in flight.
+func startBlockingCheck(t *testing.T) (*grpc.Server, *blockingHealthServer, <-chan error) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	health := &blockingHealthServer{started: make(chan struct{}), release: make(chan struct{})}
+	healthpb.RegisterHealthServer(srv, health)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		result <- err
+	}()
+	select {
+	case <-health.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("rpc did not start")
+	}
+	return srv, health, result
+}
+
+func TestGracefulStopCompletesInFlightRPC(t *testing.T) {
+	srv, health, result := startBlockingCheck(t)
+
+	stopped := make(chan struct{})
+	go func() {
+		gracefulStop(srv, 5*time.Second)
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("gracefulStop returned with an rpc in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(health.release)
+	if err := <-result; err != nil {
+		t.Fatalf("in-flight rpc failed: %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("gracefulStop did not return after the rpc completed")
+	}
+}
+
+func TestGracefulStopForcesStopAfterTimeout(t *testing.T) {
+	srv, _, result := startBlockingCheck(t)
+
+	timeout := 200 * time.Millisecond
+	start := time.Now()
+	gracefulStop(srv, timeout)
+	if elapsed := time.Since(start); elapsed < timeout {
+		t.Fatalf("gracefulStop returned after %s, before the %s timeout", elapsed, timeout)
+	}
+
+	select {
+	case err := <-result:
+		if code := status.Code(err); code == codes.OK {
+			t.Fatal("in-flight rpc succeeded after a forced stop")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight rpc was not canceled by the forced stop")
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+	"github.com/lib/pq"
+)
+
+const (
+	maxTagNameLength = 50
+	maxReviewTags    = 10
+)
+
+// uniqueViolation is the postgres error code of unique constraint violations.
+const uniqueViolation = "23505"
+
+func (srv reviewService) CreateTag(ctx context.Context, req *pb.CreateTagRequest) (*pb.CreateTagResponse, error) {
+	name := strings.TrimSpace(req.GetName())
+	if violations := validateTagName(name); len(violations) > 0 {
+		return nil, errInvalidArgument(violations...)
+	}
+
+	tag, err := srv.queries.InsertTag(ctx, repository.InsertTagParams{
+		Name:      name,
+		Sentiment: req.GetSentiment().String(),
+	})
+	if isUniqueViolation(err) {
+		return nil, errTagExists(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateTagResponse{
+		Message: localize(ctx, msgTagSaved),
+		Tag:     toPbTag(tag),
+	}, nil
+}
+
+func (srv reviewService) UpdateTag(ctx context.Context, req *pb.UpdateTagRequest) (*pb.UpdateTagResponse, error) {
+	params := repository.UpdateTagParams{ID: req.GetTagId()}
+	name := strings.TrimSpace(req.GetName())
+	if req.Name != nil {
+		if violations := validateTagName(name); len(violations) > 0 {
+			return nil, errInvalidArgument(violations...)
+		}
+		params.Name = sql.NullString{String: name, Valid: true}
+	}
+	if req.Sentiment != nil {
+		params.Sentiment = sql.NullString{String: req.GetSentiment().String(), Valid: true}
+	}
+	if req.Active != nil {
+		params.Active = sql.NullBool{Bool: req.GetActive(), Valid: true}
+	}
+
+	tag, err := srv.queries.UpdateTag(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotFound("tag", req.GetTagId())
+	}
+	if isUniqueViolation(err) {
+		return nil, errTagExists(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateTagResponse{
+		Message: localize(ctx, msgTagSaved),
+		Tag:     toPbTag(tag),
+	}, nil
+}
+
+func (srv reviewService) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+	if req.GetIncludeInactive() {
+		if caller, ok := principalFromContext(ctx); !ok || caller.role != pb.UserRole_admin {
+			return nil, errForbidden("INACTIVE_TAGS_ADMIN_ONLY")
+		}
+	}
+
+	tags, err := srv.queries.ListTags(ctx, req.GetIncludeInactive())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListTagsResponse{}
+	for _, tag := range tags {
+		resp.ListTag = append(resp.ListTag, toPbTag(tag))
+	}
+	return resp, nil
+}
+
+func (srv reviewService) GetProductTagFrequencies(ctx context.Context, req *pb.GetProductTagFrequenciesRequest) (*pb.GetProductTagFrequenciesResponse, error) {
+	rows, err := srv.queries.GetProductTagFrequencies(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetProductTagFrequenciesResponse{}
+	for _, row := range rows {
+		resp.ListTagFrequency = append(resp.ListTagFrequency, &pb.TagFrequency{
+			Tag: toPbTag(repository.Tag{
+				ID:        row.ID,
+				Name:      row.Name,
+				Sentiment: row.Sentiment,
+				Active:    row.Active,
+			}),
+			ReviewCount: row.ReviewCount,
+		})
+	}
+	return resp, nil
+}
+
+// checkReviewTags returns the distinct tags of a new review, failing when
+// one of them is unknown or retired.
+func (srv reviewService) checkReviewTags(ctx context.Context, tagIDs []int64) ([]repository.Tag, error) {
+	ids := uniqueIDs(tagIDs)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	tags, err := srv.queries.GetActiveTagsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(ids) {
+		return nil, errInvalidArgument(fieldViolation{field: "tag_ids", description: msgTagInvalid})
+	}
+	return tags, nil
+}
+
+func validateTagName(name string) []fieldViolation {
+	if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+		return []fieldViolation{{field: "name", description: msgTagNameLength, args: []interface{}{maxTagNameLength}}}
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// uniqueIDs returns ids without duplicates, in their original order.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func toPbTags(tags []repository.Tag) []*pb.Tag {
+	result := make([]*pb.Tag, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, toPbTag(tag))
+	}
+	return result
+}
+
+func toPbTag(tag repository.Tag) *pb.Tag {
+	return &pb.Tag{
+		TagId:     tag.ID,
+		Name:      tag.Name,
+		Sentiment: pb.TagSentiment(pb.TagSentiment_value[tag.Sentiment]),
+		Active:    tag.Active,
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// certReloader keeps a key pair and a CA bundle loaded from disk and reloads
+// them when the files change, so rotated certificates are used without a
+// restart. Failed reloads keep the previous material.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// newCertReloader loads the files; certFile/keyFile and caFile are each optional.
+func newCertReloader(certFile, keyFile, caFile string) (*certReloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("certificate and key files must be set together")
+	}
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		modTimes: make(map[string]time.Time),
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) files() []string {
+	var files []string
+	for _, f := range []string{r.certFile, r.keyFile, r.caFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (r *certReloader) reload() error {
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("load key pair: %w", err)
+		}
+		cert = &pair
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("read ca file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in %s", r.caFile)
+		}
+	}
+
+	modTimes := make(map[string]time.Time)
+	for _, f := range r.files() {
+		if info, err := os.Stat(f); err == nil {
+			modTimes[f] = info.ModTime()
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.caPool, r.modTimes = cert, pool, modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+// changed reports whether any file was modified since the last reload.
+func (r *certReloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[f]) {
+			return true
+		}
+	}
+	return false
+}
+
+// run polls the files every interval until ctx is done.
+func (r *certReloader) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				log.Println("tls: reload failed, keeping previous certificates: ", err)
+				continue
+			}
+			log.Printf("tls: reloaded %v", r.files())
+		}
+	}
+}
+
+func (r *certReloader) certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+func (r *certReloader) pool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.caPool
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":            tls.NoClientCert,
+	"request":         tls.RequestClientCert,
+	"verify_if_given": tls.VerifyClientCertIfGiven,
+	"require":         tls.RequireAndVerifyClientCert,
+}
+
+// serverCredentials returns the transport credentials of the grpc server:
+// TLS with TLS_CERT_FILE/TLS_KEY_FILE, and client certificates checked
+// against TLS_CLIENT_CA_FILE according to TLS_CLIENT_AUTH. It returns
+// insecure credentials when no certificate is configured.
+func serverCredentials(ctx context.Context) (credentials.TransportCredentials, error) {
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	if certFile == "" && keyFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	clientAuth, ok := clientAuthTypes[getEnv("TLS_CLIENT_AUTH", "none")]
+	if !ok {
+		return nil, fmt.Errorf("unknown TLS_CLIENT_AUTH %q", os.Getenv("TLS_CLIENT_AUTH"))
+	}
+	caFile := os.Getenv("TLS_CLIENT_CA_FILE")
+	if clientAuth > tls.RequestClientCert && caFile == "" {
+		return nil, errors.New("TLS_CLIENT_AUTH requires TLS_CLIENT_CA_FILE")
+	}
+
+	reloader, err := newCertReloader(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	go reloader.run(ctx, getEnvDuration("TLS_RELOAD_INTERVAL", time.Minute))
+
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// a config per handshake picks up rotated certificates and CAs
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2"},
+				Certificates: []tls.Certificate{*reloader.certificate()},
+				ClientAuth:   clientAuth,
+				ClientCAs:    reloader.pool(),
+			}, nil
+		},
+	}), nil
+}
+
+// clientCredentials returns the transport credentials used to dial the
+// dependencies. CLIENT_TLS_CA_FILE enables TLS, and CLIENT_TLS_CERT_FILE/
+// CLIENT_TLS_KEY_FILE additionally present a client certificate for mTLS.
+// It returns insecure credentials when TLS is not configured.
+func clientCredentials(ctx context.Context) (credentials.TransportCredentials, error) {
+	caFile := os.Getenv("CLIENT_TLS_CA_FILE")
+	if caFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	reloader, err := newCertReloader(os.Getenv("CLIENT_TLS_CERT_FILE"), os.Getenv("CLIENT_TLS_KEY_FILE"), caFile)
+	if err != nil {
+		return nil, err
+	}
+	go reloader.run(ctx, getEnvDuration("TLS_RELOAD_INTERVAL", time.Minute))
+
+	return &reloadingClientCreds{
+		TransportCredentials: credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}),
+		reloader:             reloader,
+	}, nil
+}
+
+// reloadingClientCreds builds TLS credentials from the current CA bundle and
+// client certificate on every handshake, so new connections use rotated files.
+type reloadingClientCreds struct {
+	credentials.TransportCredentials
+	reloader *certReloader
+}
+
+func (c *reloadingClientCreds) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    c.reloader.pool(),
+	}
+	if cert := c.reloader.certificate(); cert != nil {
+		cfg.Certificates = []tls.Certificate{*cert}
+	}
+	return credentials.NewTLS(cfg).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *reloadingClientCreds) Clone() credentials.TransportCredentials {
+	return &reloadingClientCreds{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		reloader:             c.reloader,
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+func (srv reviewService) GetMyReviews(ctx context.Context, req *pb.GetMyReviewsRequest) (*pb.GetMyReviewsResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, nextToken, err := srv.listUserReviews(ctx, caller.userID, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	// product details are best effort, the reviews are listed without them
+	// when order service fails
+	products := map[int64]*pb.Order{}
+	resp, err := srv.orderClient.GetHandledOrderByCustomer(forwardMetadata(ctx), &pb.GetHandledOrderByCustomerRequest{})
+	if err != nil {
+		log.Println("error when get handled orders: ", err)
+	}
+	for _, order := range resp.GetListOrder() {
+		products[order.GetProductId()] = order
+	}
+	for _, review := range reviews {
+		if order, ok := products[review.GetReview().GetProductId()]; ok {
+			review.ProductName = order.GetProductName()
+			review.ProductImage = order.GetProductImage()
+		}
+	}
+
+	return &pb.GetMyReviewsResponse{
+		ListReview:    reviews,
+		NextPageToken: nextToken,
+	}, nil
+}
+
+// GetReviewsByUser leaves product details empty: order service only lists
+// the orders of the caller, and no dependency resolves a product by id.
+func (srv reviewService) GetReviewsByUser(ctx context.Context, req *pb.GetReviewsByUserRequest) (*pb.GetReviewsByUserResponse, error) {
+	if req.GetUserId() <= 0 {
+		return nil, errInvalidArgument(fieldViolation{field: "user_id", description: msgUserIDInvalid})
+	}
+
+	reviews, nextToken, err := srv.listUserReviews(ctx, req.GetUserId(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetReviewsByUserResponse{
+		ListReview:    reviews,
+		NextPageToken: nextToken,
+	}, nil
+}
+
+// listUserReviews returns a page of the reviews written by a user, newest
+// first, and the token of the next page.
+func (srv reviewService) listUserReviews(ctx context.Context, userID int64, pageSize int32, pageToken string) ([]*pb.UserReview, string, error) {
+	beforeID, err := decodePageToken(pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	pageSize = normalizePageSize(pageSize)
+
+	reviews, err := srv.queries.ListReviewsByUser(ctx, repository.ListReviewsByUserParams{
+		UserID:   userID,
+		BeforeID: beforeID,
+		Limit:    pageSize,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(reviews) == 0 {
+		return nil, "", nil
+	}
+
+	pbReviews, err := srv.toPbReviews(ctx, reviews)
+	if err != nil {
+		return nil, "", err
+	}
+	result := make([]*pb.UserReview, 0, len(pbReviews))
+	for _, review := range pbReviews {
+		result = append(result, &pb.UserReview{Review: review})
+	}
+	return result, nextPageToken(len(reviews), pageSize, reviews[len(reviews)-1].ID), nil
+}
+
+// ListReviewableOrders has no age window: pb.Order carries no timestamp, so
+// recent orders can't be told from old ones.
+func (srv reviewService) ListReviewableOrders(ctx context.Context, req *pb.ListReviewableOrdersRequest) (*pb.ListReviewableOrdersResponse, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	beforeID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	pageSize := normalizePageSize(req.GetPageSize())
+
+	resp, err := srv.orderClient.GetHandledOrderByCustomer(forwardMetadata(ctx), &pb.GetHandledOrderByCustomerRequest{})
+	if err != nil {
+		return nil, fromDependency("order-service", err)
+	}
+
+	// reviews are per product, so only the latest order of each is kept
+	latest := map[int64]*pb.Order{}
+	for _, order := range resp.GetListOrder() {
+		if prev, ok := latest[order.GetProductId()]; !ok || order.GetOrderId() > prev.GetOrderId() {
+			latest[order.GetProductId()] = order
+		}
+	}
+	if len(latest) == 0 {
+		return &pb.ListReviewableOrdersResponse{}, nil
+	}
+	productIDs := make([]int64, 0, len(latest))
+	for productID := range latest {
+		productIDs = append(productIDs, productID)
+	}
+
+	reviewed, err := srv.queries.GetReviewedProductIDs(ctx, repository.GetReviewedProductIDsParams{
+		UserID:     caller.userID,
+		ProductIds: productIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, productID := range reviewed {
+		delete(latest, productID)
+	}
+
+	orders := make([]*pb.Order, 0, len(latest))
+	for _, order := range latest {
+		if beforeID == 0 || order.GetOrderId() < beforeID {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].GetOrderId() > orders[j].GetOrderId()
+	})
+
+	result := &pb.ListReviewableOrdersResponse{}
+	if len(orders) > int(pageSize) {
+		orders = orders[:pageSize]
+		result.NextPageToken = encodePageToken(orders[len(orders)-1].GetOrderId())
+	}
+	for _, order := range orders {
+		result.ListOrder = append(result.ListOrder, &pb.ReviewableOrder{
+			ProductId:    order.GetProductId(),
+			ProductName:  order.GetProductName(),
+			ProductImage: order.GetProductImage(),
+			OrderId:      order.GetOrderId(),
+		})
+	}
+	return result, nil
+}
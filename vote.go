@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+)
+
+// voteCounts are the helpful and not helpful counters of a review.
+type voteCounts = repository.AddReviewVoteCountsRow
+
+func (srv reviewService) VoteReview(ctx context.Context, req *pb.VoteReviewRequest) (*pb.VoteReviewResponse, error) {
+	counts, err := srv.changeVote(ctx, req.GetReviewId(), func(q *repository.Queries, userID int64) (int32, int32, error) {
+		err := q.UpsertReviewVote(ctx, repository.UpsertReviewVoteParams{
+			ReviewID: req.GetReviewId(),
+			UserID:   userID,
+			Helpful:  req.GetHelpful(),
+		})
+		if req.GetHelpful() {
+			return 1, 0, err
+		}
+		return 0, 1, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.VoteReviewResponse{
+		Message:         localize(ctx, msgReviewVoted),
+		HelpfulCount:    counts.HelpfulCount,
+		NotHelpfulCount: counts.NotHelpfulCount,
+	}, nil
+}
+
+func (srv reviewService) RetractVote(ctx context.Context, req *pb.RetractVoteRequest) (*pb.RetractVoteResponse, error) {
+	counts, err := srv.changeVote(ctx, req.GetReviewId(), func(q *repository.Queries, userID int64) (int32, int32, error) {
+		return 0, 0, q.DeleteReviewVote(ctx, repository.DeleteReviewVoteParams{
+			ReviewID: req.GetReviewId(),
+			UserID:   userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RetractVoteResponse{
+		Message:         localize(ctx, msgVoteRetracted),
+		HelpfulCount:    counts.HelpfulCount,
+		NotHelpfulCount: counts.NotHelpfulCount,
+	}, nil
+}
+
+// changeVote replaces the caller's vote on a published review with the one
+// stored by apply, which returns the counts its vote adds, and keeps the
+// review counters in step. Votes on a review are serialized by its row lock.
+func (srv reviewService) changeVote(ctx context.Context, reviewID int64, apply func(q *repository.Queries, userID int64) (int32, int32, error)) (voteCounts, error) {
+	caller, err := mustPrincipal(ctx)
+	if err != nil {
+		return voteCounts{}, err
+	}
+	if reviewID <= 0 {
+		return voteCounts{}, errInvalidArgument(fieldViolation{field: "review_id", description: msgReviewIDInvalid})
+	}
+
+	var counts voteCounts
+	err = srv.execTx(ctx, func(q *repository.Queries) error {
+		review, err := q.GetReviewForUpdate(ctx, reviewID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errNotFound("review", reviewID)
+		}
+		if err != nil {
+			return err
+		}
+		if review.Status != repository.ReviewStatusApproved {
+			return errNotFound("review", reviewID)
+		}
+		if review.UserID == caller.userID {
+			return errForbidden("OWN_REVIEW")
+		}
+
+		// the previous vote is taken back before the new one is counted
+		var helpfulDelta, notHelpfulDelta int32
+		previous, err := q.GetReviewVote(ctx, repository.GetReviewVoteParams{
+			ReviewID: reviewID,
+			UserID:   caller.userID,
+		})
+		switch {
+		case err == nil && previous:
+			helpfulDelta--
+		case err == nil:
+			notHelpfulDelta--
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		helpful, notHelpful, err := apply(q, caller.userID)
+		if err != nil {
+			return err
+		}
+		counts, err = q.AddReviewVoteCounts(ctx, repository.AddReviewVoteCountsParams{
+			ID:              reviewID,
+			HelpfulDelta:    helpfulDelta + helpful,
+			NotHelpfulDelta: notHelpfulDelta + notHelpful,
+		})
+		return err
+	})
+	return counts, err
+}
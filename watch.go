@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+	"github.com/e-commerce-microservices/review-service/repository"
+	"github.com/lib/pq"
+)
+
+// reviewEventsChannel is the postgres notification channel of review events.
+const reviewEventsChannel = "review_events"
+
+// reviewNotification is the payload of review event notifications. It only
+// carries ids, as notifications are limited to 8000 bytes.
+type reviewNotification struct {
+	Type      string `json:"type"`
+	ReviewID  int64  `json:"review_id"`
+	ProductID int64  `json:"product_id"`
+	// WasPublished tells whether watchers could see the review before the
+	// change, i.e. it was approved
+	WasPublished bool `json:"was_published"`
+}
+
+func (srv reviewService) WatchProductReviews(req *pb.WatchProductReviewsRequest, stream pb.ReviewService_WatchProductReviewsServer) error {
+	if req.GetProductId() <= 0 {
+		return errInvalidArgument(fieldViolation{field: "product_id", description: msgProductIDInvalid})
+	}
+
+	sub, err := srv.broker.subscribe(req.GetProductId())
+	if err != nil {
+		return err
+	}
+	defer srv.broker.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.done:
+			return sub.err
+		case event := <-sub.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// subscriber receives the events of a product until it is evicted.
+type subscriber struct {
+	productID int64
+	events    chan *pb.ReviewEvent
+	// done is closed when the subscriber is evicted, with the reason in err
+	done chan struct{}
+	err  error
+}
+
+// reviewBroker fans review events out to the subscribers of their product.
+// Each subscriber has its own buffer, and is evicted when it is full rather
+// than slowing down the others.
+type reviewBroker struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[*subscriber]struct{}
+	bufferSize  int
+	closed      bool
+}
+
+func newReviewBroker(bufferSize int) *reviewBroker {
+	return &reviewBroker{
+		subscribers: map[int64]map[*subscriber]struct{}{},
+		bufferSize:  bufferSize,
+	}
+}
+
+func (b *reviewBroker) subscribe(productID int64) (*subscriber, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, errShuttingDown()
+	}
+	sub := &subscriber{
+		productID: productID,
+		events:    make(chan *pb.ReviewEvent, b.bufferSize),
+		done:      make(chan struct{}),
+	}
+	if b.subscribers[productID] == nil {
+		b.subscribers[productID] = map[*subscriber]struct{}{}
+	}
+	b.subscribers[productID][sub] = struct{}{}
+	return sub, nil
+}
+
+func (b *reviewBroker) unsubscribe(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// watched reports whether the product has subscribers.
+func (b *reviewBroker) watched(productID int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers[productID]) > 0
+}
+
+func (b *reviewBroker) publish(productID int64, event *pb.ReviewEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers[productID] {
+		select {
+		case sub.events <- event:
+		default:
+			sub.err = errSlowConsumer()
+			b.remove(sub)
+			close(sub.done)
+		}
+	}
+}
+
+// close evicts every subscriber, so their streams end before shutdown.
+func (b *reviewBroker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			sub.err = errShuttingDown()
+			b.remove(sub)
+			close(sub.done)
+		}
+	}
+}
+
+// remove must be called with b.mu held.
+func (b *reviewBroker) remove(sub *subscriber) {
+	subs := b.subscribers[sub.productID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.productID)
+	}
+}
+
+// notifyReviewEvent notifies the listeners of all replicas when the caller's
+// transaction commits.
+func notifyReviewEvent(ctx context.Context, q *repository.Queries, eventType string, previous repository.ReviewStatus, review repository.Review) error {
+	payload, err := json.Marshal(reviewNotification{
+		Type:         eventType,
+		ReviewID:     review.ID,
+		ProductID:    review.ProductID,
+		WasPublished: previous == repository.ReviewStatusApproved,
+	})
+	if err != nil {
+		return err
+	}
+	return q.NotifyReviewEvent(ctx, string(payload))
+}
+
+// reviewListener feeds the broker with the review events notified by every
+// replica, this one included.
+type reviewListener struct {
+	dsn    string
+	srv    reviewService
+	broker *reviewBroker
+	// retryDelay is the wait between attempts to listen
+	retryDelay time.Duration
+}
+
+// run listens until ctx is done, then closes the broker. Watchers stay
+// subscribed while the listener is retrying or reconnecting, they only miss
+// the changes notified in the meantime.
+func (l reviewListener) run(ctx context.Context) {
+	defer l.broker.close()
+
+	listener := pq.NewListener(l.dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("review listener: ", err)
+		}
+	})
+	// Listen blocks until connected, closing the listener stops it
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		err := listener.Listen(reviewEventsChannel)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Println("can't listen to review events, retrying: ", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryDelay):
+		}
+	}
+
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			go func() {
+				if err := listener.Ping(); err != nil {
+					log.Println("review listener ping: ", err)
+				}
+			}()
+		case n, ok := <-listener.Notify:
+			if !ok {
+				return
+			}
+			// nil after the connection was re-established
+			if n == nil {
+				continue
+			}
+			var notification reviewNotification
+			if err := json.Unmarshal([]byte(n.Extra), &notification); err != nil {
+				log.Println("invalid review notification: ", err)
+				continue
+			}
+			l.dispatch(ctx, notification)
+		}
+	}
+}
+
+// dispatch publishes a notified change to the watchers of the product as
+// they see it: they only see approved reviews, so a review becoming approved
+// is created for them and one leaving approval is deleted.
+func (l reviewListener) dispatch(ctx context.Context, n reviewNotification) {
+	if !l.broker.watched(n.ProductID) {
+		return
+	}
+
+	var review *repository.Review
+	if n.Type != eventReviewDeleted {
+		found, err := l.srv.queries.GetReviewByID(ctx, n.ReviewID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Println("can't load notified review: ", err)
+			return
+		}
+		// deleted in the meantime unless found
+		if err == nil {
+			review = &found
+		}
+	}
+	published := review != nil && review.Status == repository.ReviewStatusApproved
+
+	switch {
+	case !published && n.WasPublished:
+		l.broker.publish(n.ProductID, &pb.ReviewEvent{
+			Type:   pb.ReviewEventType_review_deleted,
+			Review: &pb.Review{ReviewId: n.ReviewID, ProductId: n.ProductID},
+		})
+	case published:
+		result, err := l.srv.toPbReviews(ctx, []repository.Review{*review})
+		if err != nil {
+			log.Println("can't load notified review: ", err)
+			return
+		}
+		eventType := pb.ReviewEventType_review_created
+		if n.WasPublished {
+			eventType = pb.ReviewEventType_review_updated
+		}
+		l.broker.publish(n.ProductID, &pb.ReviewEvent{Type: eventType, Review: result[0]})
+	}
+}
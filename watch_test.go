@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/e-commerce-microservices/review-service/pb"
+)
+
+func TestReviewBrokerEvictsSlowConsumer(t *testing.T) {
+	broker := newReviewBroker(1)
+	slow, err := broker.subscribe(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := broker.subscribe(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker.publish(1, &pb.ReviewEvent{})
+	broker.publish(1, &pb.ReviewEvent{})
+
+	select {
+	case <-slow.done:
+	default:
+		t.Fatal("slow consumer not evicted")
+	}
+	var reviewErr *reviewError
+	if !errors.As(slow.err, &reviewErr) || reviewErr.reason != "SLOW_CONSUMER" {
+		t.Errorf("evicted with %v, want SLOW_CONSUMER", slow.err)
+	}
+	if broker.watched(1) || !broker.watched(2) {
+		t.Error("only the slow consumer must be removed")
+	}
+	select {
+	case <-other.done:
+		t.Error("consumer of another product evicted")
+	default:
+	}
+}
+
+func TestDispatchDeletedReviewOnlyIfPublished(t *testing.T) {
+	broker := newReviewBroker(10)
+	sub, err := broker.subscribe(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := reviewListener{broker: broker}
+
+	// watchers never saw the pending review, so its deletion is not theirs
+	listener.dispatch(context.Background(), reviewNotification{Type: eventReviewDeleted, ReviewID: 7, ProductID: 1})
+	listener.dispatch(context.Background(), reviewNotification{Type: eventReviewDeleted, ReviewID: 8, ProductID: 1, WasPublished: true})
+
+	select {
+	case event := <-sub.events:
+		if event.GetType() != pb.ReviewEventType_review_deleted || event.GetReview().GetReviewId() != 8 {
+			t.Errorf("got %v, want review 8 deleted", event)
+		}
+	default:
+		t.Fatal("deletion of the published review not sent")
+	}
+	select {
+	case event := <-sub.events:
+		t.Errorf("unexpected event %v", event)
+	default:
+	}
+}
+
+func TestReviewListenerKeepsBrokerOpenUntilStopped(t *testing.T) {
+	broker := newReviewBroker(1)
+	listener := reviewListener{
+		// nothing listens there, so the listener keeps reconnecting
+		dsn:        "host=127.0.0.1 port=1 sslmode=disable connect_timeout=1",
+		broker:     broker,
+		retryDelay: 10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		listener.run(ctx)
+		close(stopped)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	sub, err := broker.subscribe(1)
+	if err != nil {
+		t.Fatalf("subscribe while the listener is not connected: %v", err)
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener not stopped")
+	}
+	select {
+	case <-sub.done:
+	default:
+		t.Error("watcher not evicted on shutdown")
+	}
+	if _, err := broker.subscribe(1); err == nil {
+		t.Error("subscribe accepted after shutdown")
+	}
+}